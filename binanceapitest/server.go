@@ -0,0 +1,1316 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package binanceapitest provides an in-process fake of the Binance REST API
+// for testing code that uses binanceapi.RestClient without network access.
+//
+//	server := binanceapitest.NewServer()
+//	defer server.Close()
+//	client := server.Client()
+//
+// The server keeps orders, balances and trades in memory, verifies the API
+// key and signature of signed requests, and can be scripted to return
+// specific responses or errors.
+package binanceapitest
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+)
+
+const (
+	DefaultApiKey = "binanceapitest-api-key"
+	DefaultSecret = "binanceapitest-secret"
+)
+
+// Response is a scripted response returned instead of the normal handling
+// of a request.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+// ErrorResponse returns a scripted Binance error response.
+func ErrorResponse(statusCode int, code binanceapi.ErrorCode, msg string) Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"code": code,
+		"msg":  msg,
+	})
+	return Response{
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+
+	// Query and body parameters, without the signature.
+	Params url.Values
+}
+
+type symbol struct {
+	info     binanceapi.SymbolInfoResponse
+	price    float64
+	bidPrice float64
+	bidQty   float64
+	askPrice float64
+	askQty   float64
+	updateId int64
+}
+
+type balance struct {
+	free   float64
+	locked float64
+}
+
+type order struct {
+	symbol        string
+	orderId       int64
+	clientOrderId string
+	price         float64
+	origQty       float64
+	executedQty   float64
+	quoteQty      float64
+	status        binanceapi.OrderStatus
+	timeInForce   binanceapi.TimeInForce
+	orderType     binanceapi.OrderType
+	side          binanceapi.OrderSide
+	time          int64
+	updateTime    int64
+}
+
+type trade struct {
+	symbol   string
+	id       int64
+	orderId  int64
+	price    float64
+	qty      float64
+	time     int64
+	isBuyer  bool
+	isMaker  bool
+	quoteQty float64
+
+	isBuyerMaker bool
+}
+
+// Server is a fake Binance REST API server.
+type Server struct {
+	*httptest.Server
+
+	ApiKey string
+	Secret string
+
+	// PublicKey, if set to an *rsa.PublicKey or ed25519.PublicKey, verifies
+	// signatures instead of Secret, as for an RSA or Ed25519 API key. The
+	// client must then be given the matching signer, eg.
+	// Client(binanceapi.WithSigner(server.ApiKey, signer)).
+	PublicKey crypto.PublicKey
+
+	// Now returns the server time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu          sync.Mutex
+	symbols     map[string]*symbol
+	balances    map[string]*balance
+	orders      []*order
+	trades      []*trade
+	listenKeys  map[string]bool
+	nextOrderId int64
+	nextTradeId int64
+	scripted    map[string][]Response
+	requests    []Request
+	weight      int64
+	weightMin   int64
+}
+
+// NewServer starts a server with BTCUSDT and ETHUSDT markets, an account
+// with 10000 USDT, and the default API key and secret.
+func NewServer() *Server {
+	s := &Server{
+		ApiKey:      DefaultApiKey,
+		Secret:      DefaultSecret,
+		Now:         time.Now,
+		symbols:     map[string]*symbol{},
+		balances:    map[string]*balance{},
+		listenKeys:  map[string]bool{},
+		nextOrderId: 1,
+		nextTradeId: 1,
+		scripted:    map[string][]Response{},
+	}
+	s.AddSymbol("BTCUSDT", "BTC", "USDT", 50000)
+	s.AddSymbol("ETHUSDT", "ETH", "USDT", 3000)
+	s.SetBalance("USDT", 10000)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Client returns a RestClient for the server authenticated with its API key
+// and secret.
+func (s *Server) Client(options ...binanceapi.RestClientOption) *binanceapi.RestClient {
+	options = append([]binanceapi.RestClientOption{
+		binanceapi.WithBaseUrl(s.URL),
+		binanceapi.WithAuth(s.ApiKey, s.Secret),
+	}, options...)
+	return binanceapi.NewRestClient(options...)
+}
+
+// AddSymbol adds a market trading at price with a spread of one tick.
+func (s *Server) AddSymbol(name string, baseAsset string, quoteAsset string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.symbols[name] = &symbol{
+		info: binanceapi.SymbolInfoResponse{
+			Symbol:              name,
+			Status:              "TRADING",
+			BaseAsset:           baseAsset,
+			BaseAssetPrecision:  8,
+			QuoteAsset:          quoteAsset,
+			QuoteAssetPrecision: 8,
+			OrderTypes:          []string{"LIMIT", "MARKET"},
+			IcebergAllowed:      true,
+			Filters: []binanceapi.SymbolFilterResponse{
+				{FilterType: "PRICE_FILTER", MinPrice: 0.01, MaxPrice: 1000000, TickSize: 0.01},
+				{FilterType: "LOT_SIZE", MinQty: 0.00001, MaxQty: 9000, StepSize: 0.00001},
+			},
+		},
+	}
+	s.setPrice(name, price)
+}
+
+// SetPrice sets the last price of a symbol, with the best bid and ask one
+// tick either side.
+func (s *Server) SetPrice(name string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setPrice(name, price)
+}
+
+func (s *Server) setPrice(name string, price float64) {
+	sym := s.symbols[name]
+	sym.price = price
+	sym.bidPrice = price - 0.01
+	sym.bidQty = 1
+	sym.askPrice = price + 0.01
+	sym.askQty = 1
+	sym.updateId++
+}
+
+// AddTrade records a trade on the market by another account, as returned by
+// the public trade endpoints.
+func (s *Server) AddTrade(name string, price float64, qty float64, isBuyerMaker bool, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades = append(s.trades, &trade{
+		symbol:       name,
+		id:           s.nextTradeId,
+		price:        price,
+		qty:          qty,
+		quoteQty:     price * qty,
+		time:         at.UnixNano() / int64(time.Millisecond),
+		isBuyerMaker: isBuyerMaker,
+	})
+	s.nextTradeId++
+}
+
+// SetBalance sets the free balance of an asset.
+func (s *Server) SetBalance(asset string, free float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance(asset).free = free
+}
+
+// Balance returns the free and locked balance of an asset.
+func (s *Server) Balance(asset string) (free float64, locked float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.balance(asset)
+	return b.free, b.locked
+}
+
+func (s *Server) balance(asset string) *balance {
+	b, ok := s.balances[asset]
+	if !ok {
+		b = &balance{}
+		s.balances[asset] = b
+	}
+	return b
+}
+
+// Script queues responses for method and path, eg. "POST" and
+// "/api/v3/order". Each request takes the next response until the queue is
+// empty, after which requests are handled normally again.
+func (s *Server) Script(method string, path string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := method + " " + path
+	s.scripted[key] = append(s.scripted[key], responses...)
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+type apiError struct {
+	statusCode int
+	code       binanceapi.ErrorCode
+	msg        string
+}
+
+func newApiError(statusCode int, code binanceapi.ErrorCode, msg string) *apiError {
+	return &apiError{statusCode, code, msg}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	query, signature := stripSignature(r.URL.RawQuery)
+	form, bodySignature := stripSignature(string(body))
+	if signature == "" {
+		signature = bodySignature
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		s.writeError(w, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeIllegalChars, err.Error()))
+		return
+	}
+	formParams, err := url.ParseQuery(form)
+	if err != nil {
+		s.writeError(w, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeIllegalChars, err.Error()))
+		return
+	}
+	for key, values := range formParams {
+		params[key] = append(params[key], values...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Params: params,
+	})
+
+	minute := s.Now().Unix() / 60
+	if minute != s.weightMin {
+		s.weightMin = minute
+		s.weight = 0
+	}
+	s.weight++
+	w.Header().Set("X-MBX-USED-WEIGHT-1M", strconv.FormatInt(s.weight, 10))
+
+	key := r.Method + " " + r.URL.Path
+	if responses := s.scripted[key]; len(responses) > 0 {
+		s.scripted[key] = responses[1:]
+		s.writeScripted(w, responses[0])
+		return
+	}
+
+	route, ok := routes[key]
+	if !ok {
+		s.writeError(w, newApiError(http.StatusNotFound, binanceapi.ErrorCodeUnknown,
+			fmt.Sprintf("unknown endpoint %s", key)))
+		return
+	}
+
+	if route.security.RequiresApiKey() && r.Header.Get("X-MBX-APIKEY") != s.ApiKey {
+		s.writeError(w, newApiError(http.StatusUnauthorized, binanceapi.ErrorCodeRejectedApiKey,
+			"Invalid API-key, IP, or permissions for action."))
+		return
+	}
+	if route.security.RequiresSignature() {
+		if apiErr := s.verify(query+form, signature, params); apiErr != nil {
+			s.writeError(w, apiErr)
+			return
+		}
+	}
+
+	response, apiErr := route.handler(s, params)
+	if apiErr != nil {
+		s.writeError(w, apiErr)
+		return
+	}
+	s.writeJson(w, http.StatusOK, response)
+}
+
+// stripSignature removes the signature parameter from an encoded parameter
+// string, returning the remainder and the decoded signature.
+func stripSignature(encoded string) (string, string) {
+	i := strings.Index(encoded, "signature=")
+	if i < 0 || (i > 0 && encoded[i-1] != '&') {
+		return encoded, ""
+	}
+	rest := encoded[i+len("signature="):]
+	value := rest
+	remainder := strings.TrimSuffix(encoded[:i], "&")
+	if j := strings.Index(rest, "&"); j > -1 {
+		value = rest[:j]
+		if remainder != "" {
+			remainder += "&"
+		}
+		remainder += rest[j+1:]
+	}
+	signature, _ := url.QueryUnescape(value)
+	return remainder, signature
+}
+
+// validSignature returns true if signature is that of payload by the key of
+// the server.
+func (s *Server) validSignature(payload string, signature string) bool {
+	if s.PublicKey == nil {
+		mac := hmac.New(sha256.New, []byte(s.Secret))
+		mac.Write([]byte(payload))
+		return signature == hex.EncodeToString(mac.Sum(nil))
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	switch key := s.PublicKey.(type) {
+	case *rsa.PublicKey:
+		hashed := sha256.Sum256([]byte(payload))
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], decoded) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, []byte(payload), decoded)
+	}
+	return false
+}
+
+func (s *Server) verify(payload string, signature string, params url.Values) *apiError {
+	if !s.validSignature(payload, signature) {
+		return newApiError(http.StatusBadRequest, binanceapi.ErrorCodeInvalidSignature,
+			"Signature for this request is not valid.")
+	}
+
+	timestamp, err := strconv.ParseInt(params.Get("timestamp"), 10, 64)
+	if err != nil {
+		return newApiError(http.StatusBadRequest, binanceapi.ErrorCodeMandatoryParamMissing,
+			"Mandatory parameter 'timestamp' was not sent, was empty/null, or malformed.")
+	}
+	recvWindow := 5000.0
+	if params.Get("recvWindow") != "" {
+		recvWindow, err = strconv.ParseFloat(params.Get("recvWindow"), 64)
+		if err != nil || recvWindow <= 0 || recvWindow > 60000 {
+			return newApiError(http.StatusBadRequest, binanceapi.ErrorCodeBadRecvWindow,
+				"recvWindow must be less than 60000")
+		}
+	}
+	now := s.Now().UnixNano() / int64(time.Millisecond)
+	if timestamp > now+1000 || float64(now-timestamp) > recvWindow {
+		return newApiError(http.StatusBadRequest, binanceapi.ErrorCodeInvalidTimestamp,
+			"Timestamp for this request is outside of the recvWindow.")
+	}
+	return nil
+}
+
+func (s *Server) writeScripted(w http.ResponseWriter, response Response) {
+	for key, values := range response.Header {
+		w.Header()[key] = values
+	}
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(statusCode)
+	w.Write([]byte(response.Body))
+}
+
+func (s *Server) writeError(w http.ResponseWriter, apiErr *apiError) {
+	s.writeJson(w, apiErr.statusCode, map[string]interface{}{
+		"code": apiErr.code,
+		"msg":  apiErr.msg,
+	})
+}
+
+func (s *Server) writeJson(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+type route struct {
+	security binanceapi.SecurityType
+	handler  func(s *Server, params url.Values) (interface{}, *apiError)
+}
+
+var routes = map[string]route{
+	"GET /api/v1/ping":              {binanceapi.SecurityTypeNone, (*Server).ping},
+	"GET /api/v3/ping":              {binanceapi.SecurityTypeNone, (*Server).ping},
+	"GET /api/v1/time":              {binanceapi.SecurityTypeNone, (*Server).time},
+	"GET /api/v3/time":              {binanceapi.SecurityTypeNone, (*Server).time},
+	"GET /api/v1/exchangeInfo":      {binanceapi.SecurityTypeNone, (*Server).exchangeInfo},
+	"GET /api/v3/exchangeInfo":      {binanceapi.SecurityTypeNone, (*Server).exchangeInfo},
+	"GET /api/v3/ticker/price":      {binanceapi.SecurityTypeNone, (*Server).tickerPrice},
+	"GET /api/v3/ticker/bookTicker": {binanceapi.SecurityTypeNone, (*Server).bookTicker},
+	"GET /api/v3/ticker/24hr":       {binanceapi.SecurityTypeNone, (*Server).ticker24hr},
+	"GET /api/v3/depth":             {binanceapi.SecurityTypeNone, (*Server).depth},
+	"GET /api/v3/trades":            {binanceapi.SecurityTypeNone, (*Server).recentTrades},
+	"GET /api/v3/historicalTrades":  {binanceapi.SecurityTypeMarketData, (*Server).historicalTrades},
+	"GET /api/v3/aggTrades":         {binanceapi.SecurityTypeNone, (*Server).aggTrades},
+	"GET /api/v3/klines":            {binanceapi.SecurityTypeNone, (*Server).klines},
+	"GET /api/v3/uiKlines":          {binanceapi.SecurityTypeNone, (*Server).klines},
+	"POST /api/v3/order":            {binanceapi.SecurityTypeTrade, (*Server).postOrder},
+	"GET /api/v3/order":             {binanceapi.SecurityTypeUserData, (*Server).getOrder},
+	"DELETE /api/v3/order":          {binanceapi.SecurityTypeTrade, (*Server).deleteOrder},
+	"GET /api/v3/myTrades":          {binanceapi.SecurityTypeUserData, (*Server).myTrades},
+	"GET /api/v3/account":           {binanceapi.SecurityTypeUserData, (*Server).account},
+	"POST /api/v1/userDataStream":   {binanceapi.SecurityTypeUserStream, (*Server).postUserDataStream},
+	"PUT /api/v1/userDataStream":    {binanceapi.SecurityTypeUserStream, (*Server).putUserDataStream},
+	"DELETE /api/v1/userDataStream": {binanceapi.SecurityTypeUserStream, (*Server).deleteUserDataStream},
+	"POST /api/v3/userDataStream":   {binanceapi.SecurityTypeUserStream, (*Server).postUserDataStream},
+	"PUT /api/v3/userDataStream":    {binanceapi.SecurityTypeUserStream, (*Server).putUserDataStream},
+	"DELETE /api/v3/userDataStream": {binanceapi.SecurityTypeUserStream, (*Server).deleteUserDataStream},
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 8, 64)
+}
+
+func (s *Server) millis() int64 {
+	return s.Now().UnixNano() / int64(time.Millisecond)
+}
+
+func (s *Server) symbol(params url.Values) (*symbol, *apiError) {
+	name := params.Get("symbol")
+	if name == "" {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeMandatoryParamMissing,
+			"Mandatory parameter 'symbol' was not sent, was empty/null, or malformed.")
+	}
+	sym, ok := s.symbols[name]
+	if !ok {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeBadSymbol, "Invalid symbol.")
+	}
+	return sym, nil
+}
+
+// sortedSymbols returns the symbols ordered by name.
+func (s *Server) sortedSymbols() []*symbol {
+	symbols := []*symbol{}
+	for _, sym := range s.symbols {
+		symbols = append(symbols, sym)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].info.Symbol < symbols[j].info.Symbol
+	})
+	return symbols
+}
+
+func (s *Server) ping(params url.Values) (interface{}, *apiError) {
+	return map[string]interface{}{}, nil
+}
+
+func (s *Server) time(params url.Values) (interface{}, *apiError) {
+	return map[string]interface{}{
+		"serverTime": s.millis(),
+	}, nil
+}
+
+func (s *Server) exchangeInfo(params url.Values) (interface{}, *apiError) {
+	symbols := []binanceapi.SymbolInfoResponse{}
+	for _, sym := range s.sortedSymbols() {
+		symbols = append(symbols, sym.info)
+	}
+	return binanceapi.ExchangeInfoResponse{
+		Timezone:         "UTC",
+		ServerTimeMillis: s.millis(),
+		RateLimits: []binanceapi.RateLimit{
+			{RateLimitType: "REQUEST_WEIGHT", RateLimitInterval: "MINUTE", IntervalNum: 1, Limit: 6000},
+			{RateLimitType: "ORDERS", RateLimitInterval: "SECOND", IntervalNum: 10, Limit: 100},
+			{RateLimitType: "ORDERS", RateLimitInterval: "DAY", IntervalNum: 1, Limit: 200000},
+		},
+		Symbols: symbols,
+	}, nil
+}
+
+func tickerPrice(sym *symbol) map[string]interface{} {
+	return map[string]interface{}{
+		"symbol": sym.info.Symbol,
+		"price":  formatFloat(sym.price),
+	}
+}
+
+func (s *Server) tickerPrice(params url.Values) (interface{}, *apiError) {
+	if params.Get("symbol") == "" {
+		prices := []interface{}{}
+		for _, sym := range s.sortedSymbols() {
+			prices = append(prices, tickerPrice(sym))
+		}
+		return prices, nil
+	}
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return tickerPrice(sym), nil
+}
+
+func bookTicker(sym *symbol) map[string]interface{} {
+	return map[string]interface{}{
+		"symbol":   sym.info.Symbol,
+		"bidPrice": formatFloat(sym.bidPrice),
+		"bidQty":   formatFloat(sym.bidQty),
+		"askPrice": formatFloat(sym.askPrice),
+		"askQty":   formatFloat(sym.askQty),
+	}
+}
+
+func (s *Server) bookTicker(params url.Values) (interface{}, *apiError) {
+	if params.Get("symbol") == "" {
+		tickers := []interface{}{}
+		for _, sym := range s.sortedSymbols() {
+			tickers = append(tickers, bookTicker(sym))
+		}
+		return tickers, nil
+	}
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return bookTicker(sym), nil
+}
+
+// tickerStatistics returns the statistics of the trades of the symbol in the
+// 24 hours before now.
+func (s *Server) tickerStatistics(sym *symbol, full bool) map[string]interface{} {
+	closeTime := s.millis()
+	openTime := closeTime - int64(24*time.Hour/time.Millisecond)
+	prevClose := sym.price
+	open, high, low, last, lastQty := sym.price, sym.price, sym.price, sym.price, 0.0
+	var volume, quoteVolume float64
+	firstId, lastId, count := int64(-1), int64(-1), 0
+	for _, t := range s.symbolTrades(sym.info.Symbol) {
+		if t.time < openTime {
+			prevClose = t.price
+			continue
+		}
+		if t.time > closeTime {
+			continue
+		}
+		if count == 0 {
+			open, high, low, firstId = t.price, t.price, t.price, t.id
+		}
+		if t.price > high {
+			high = t.price
+		}
+		if t.price < low {
+			low = t.price
+		}
+		last, lastQty, lastId = t.price, t.qty, t.id
+		volume += t.qty
+		quoteVolume += t.quoteQty
+		count++
+	}
+
+	stats := map[string]interface{}{
+		"symbol":      sym.info.Symbol,
+		"openPrice":   formatFloat(open),
+		"highPrice":   formatFloat(high),
+		"lowPrice":    formatFloat(low),
+		"lastPrice":   formatFloat(last),
+		"volume":      formatFloat(volume),
+		"quoteVolume": formatFloat(quoteVolume),
+		"openTime":    openTime,
+		"closeTime":   closeTime,
+		"firstId":     firstId,
+		"lastId":      lastId,
+		"count":       count,
+	}
+	if full {
+		weightedAvg := 0.0
+		if volume > 0 {
+			weightedAvg = quoteVolume / volume
+		}
+		stats["priceChange"] = formatFloat(last - open)
+		stats["priceChangePercent"] = strconv.FormatFloat((last-open)/open*100, 'f', 3, 64)
+		stats["weightedAvgPrice"] = formatFloat(weightedAvg)
+		stats["prevClosePrice"] = formatFloat(prevClose)
+		stats["lastQty"] = formatFloat(lastQty)
+		stats["bidPrice"] = formatFloat(sym.bidPrice)
+		stats["bidQty"] = formatFloat(sym.bidQty)
+		stats["askPrice"] = formatFloat(sym.askPrice)
+		stats["askQty"] = formatFloat(sym.askQty)
+	}
+	return stats
+}
+
+func (s *Server) ticker24hr(params url.Values) (interface{}, *apiError) {
+	tickerType := params.Get("type")
+	if tickerType != "" && tickerType != "FULL" && tickerType != "MINI" {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeInvalidParameter,
+			"Invalid value for parameter 'type'.")
+	}
+	full := tickerType != "MINI"
+
+	if params.Get("symbol") != "" {
+		sym, apiErr := s.symbol(params)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return s.tickerStatistics(sym, full), nil
+	}
+
+	symbols := s.sortedSymbols()
+	if params.Get("symbols") != "" {
+		var names []string
+		if err := json.Unmarshal([]byte(params.Get("symbols")), &names); err != nil {
+			return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeIllegalChars,
+				"Illegal characters found in parameter 'symbols'.")
+		}
+		if len(names) == 0 {
+			return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeMandatoryParamMissing,
+				"Mandatory parameter 'symbols' was not sent, was empty/null, or malformed.")
+		}
+		symbols = nil
+		for _, name := range names {
+			sym, ok := s.symbols[name]
+			if !ok {
+				return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeBadSymbol, "Invalid symbol.")
+			}
+			symbols = append(symbols, sym)
+		}
+	}
+	response := []interface{}{}
+	for _, sym := range symbols {
+		response = append(response, s.tickerStatistics(sym, full))
+	}
+	return response, nil
+}
+
+// depth returns a book of limit levels either side of the best bid and ask,
+// one tick apart.
+func (s *Server) depth(params url.Values) (interface{}, *apiError) {
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	limit := 100
+	if params.Get("limit") != "" {
+		limit, _ = strconv.Atoi(params.Get("limit"))
+	}
+	if limit <= 0 || limit > 5000 {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeIllegalChars,
+			"Illegal characters found in parameter 'limit'; legal range is '1' - '5000'.")
+	}
+	bids := [][]string{}
+	asks := [][]string{}
+	for i := 0; i < limit; i++ {
+		bids = append(bids, []string{formatFloat(sym.bidPrice - float64(i)*0.01), formatFloat(sym.bidQty)})
+		asks = append(asks, []string{formatFloat(sym.askPrice + float64(i)*0.01), formatFloat(sym.askQty)})
+	}
+	return map[string]interface{}{
+		"lastUpdateId": sym.updateId,
+		"bids":         bids,
+		"asks":         asks,
+	}, nil
+}
+
+func (t *trade) publicJson() map[string]interface{} {
+	return map[string]interface{}{
+		"id":           t.id,
+		"price":        formatFloat(t.price),
+		"qty":          formatFloat(t.qty),
+		"quoteQty":     formatFloat(t.quoteQty),
+		"time":         t.time,
+		"isBuyerMaker": t.isBuyerMaker,
+		"isBestMatch":  true,
+	}
+}
+
+// symbolTrades returns the trades of a symbol, oldest first.
+func (s *Server) symbolTrades(name string) []*trade {
+	trades := []*trade{}
+	for _, t := range s.trades {
+		if t.symbol == name {
+			trades = append(trades, t)
+		}
+	}
+	return trades
+}
+
+func tradesLimit(params url.Values) (int, *apiError) {
+	limit := 500
+	if params.Get("limit") != "" {
+		limit, _ = strconv.Atoi(params.Get("limit"))
+	}
+	if limit <= 0 || limit > 1000 {
+		return 0, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeIllegalChars,
+			"Illegal characters found in parameter 'limit'; legal range is '1' - '1000'.")
+	}
+	return limit, nil
+}
+
+func (s *Server) recentTrades(params url.Values) (interface{}, *apiError) {
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	limit, apiErr := tradesLimit(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	trades := s.symbolTrades(sym.info.Symbol)
+	if len(trades) > limit {
+		trades = trades[len(trades)-limit:]
+	}
+	response := []interface{}{}
+	for _, t := range trades {
+		response = append(response, t.publicJson())
+	}
+	return response, nil
+}
+
+func (s *Server) historicalTrades(params url.Values) (interface{}, *apiError) {
+	if params.Get("fromId") == "" {
+		return s.recentTrades(params)
+	}
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	limit, apiErr := tradesLimit(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	fromId, _ := strconv.ParseInt(params.Get("fromId"), 10, 64)
+	response := []interface{}{}
+	for _, t := range s.symbolTrades(sym.info.Symbol) {
+		if t.id < fromId {
+			continue
+		}
+		if len(response) >= limit {
+			break
+		}
+		response = append(response, t.publicJson())
+	}
+	return response, nil
+}
+
+// aggTrades returns each trade as an aggregate trade of its own.
+func (s *Server) aggTrades(params url.Values) (interface{}, *apiError) {
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	limit, apiErr := tradesLimit(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	fromId := int64(-1)
+	if params.Get("fromId") != "" {
+		fromId, _ = strconv.ParseInt(params.Get("fromId"), 10, 64)
+	}
+	startTime, _ := strconv.ParseInt(params.Get("startTime"), 10, 64)
+	endTime, _ := strconv.ParseInt(params.Get("endTime"), 10, 64)
+	if startTime > 0 && endTime > 0 && endTime-startTime > int64(time.Hour/time.Millisecond) {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeMoreThanXxHours,
+			"More than 1 hours between startTime and endTime.")
+	}
+
+	trades := s.symbolTrades(sym.info.Symbol)
+	if fromId < 0 && startTime == 0 && endTime == 0 && len(trades) > limit {
+		trades = trades[len(trades)-limit:]
+	}
+	response := []interface{}{}
+	for _, t := range trades {
+		if t.id < fromId || (startTime > 0 && t.time < startTime) ||
+			(endTime > 0 && t.time > endTime) {
+			continue
+		}
+		if len(response) >= limit {
+			break
+		}
+		response = append(response, map[string]interface{}{
+			"a": t.id,
+			"p": formatFloat(t.price),
+			"q": formatFloat(t.qty),
+			"f": t.id,
+			"l": t.id,
+			"T": t.time,
+			"m": t.isBuyerMaker,
+			"M": true,
+		})
+	}
+	return response, nil
+}
+
+var klineIntervals = map[string]time.Duration{
+	"1s":  time.Second,
+	"1m":  time.Minute,
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"6h":  6 * time.Hour,
+	"8h":  8 * time.Hour,
+	"12h": 12 * time.Hour,
+	"1d":  24 * time.Hour,
+	"3d":  3 * 24 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
+}
+
+// klines builds klines from the trades of the symbol. Klines without trades
+// stay at the last price. The timeZone parameter is accepted but ignored.
+func (s *Server) klines(params url.Values) (interface{}, *apiError) {
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	interval := params.Get("interval")
+	duration, ok := klineIntervals[interval]
+	if !ok && interval != "1M" {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeBadInterval, "Invalid interval.")
+	}
+	limit := 500
+	if params.Get("limit") != "" {
+		limit, _ = strconv.Atoi(params.Get("limit"))
+	}
+	if limit <= 0 || limit > 1000 {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeIllegalChars,
+			"Illegal characters found in parameter 'limit'; legal range is '1' - '1000'.")
+	}
+
+	// The open time of the kline containing t, and of the one after it.
+	open := func(t time.Time) time.Time {
+		if interval == "1M" {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+		return t.Truncate(duration)
+	}
+	next := func(t time.Time) time.Time {
+		if interval == "1M" {
+			return t.AddDate(0, 1, 0)
+		}
+		return t.Add(duration)
+	}
+	fromMillis := func(key string) time.Time {
+		millis, _ := strconv.ParseInt(params.Get(key), 10, 64)
+		return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+	}
+
+	endTime := s.Now().UTC()
+	if params.Get("endTime") != "" {
+		endTime = fromMillis("endTime")
+	}
+	var startTime time.Time
+	if params.Get("startTime") != "" {
+		startTime = open(fromMillis("startTime"))
+		if startTime.Before(fromMillis("startTime")) {
+			startTime = next(startTime)
+		}
+	} else {
+		// The most recent limit klines.
+		startTime = open(endTime)
+		for i := 1; i < limit; i++ {
+			if interval == "1M" {
+				startTime = startTime.AddDate(0, -1, 0)
+			} else {
+				startTime = startTime.Add(-duration)
+			}
+		}
+	}
+
+	millis := func(t time.Time) int64 {
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+	trades := s.symbolTrades(sym.info.Symbol)
+	lastPrice := sym.price
+	for _, t := range trades {
+		if t.time < millis(startTime) {
+			lastPrice = t.price
+		}
+	}
+
+	response := []interface{}{}
+	for t := startTime; !t.After(endTime) && len(response) < limit; t = next(t) {
+		openTime, closeTime := millis(t), millis(next(t))-1
+		o, h, l, c := lastPrice, lastPrice, lastPrice, lastPrice
+		var volume, quoteVolume, takerVolume, takerQuoteVolume float64
+		count := 0
+		for _, tr := range trades {
+			if tr.time < openTime || tr.time > closeTime {
+				continue
+			}
+			if count == 0 {
+				o, h, l = tr.price, tr.price, tr.price
+			}
+			if tr.price > h {
+				h = tr.price
+			}
+			if tr.price < l {
+				l = tr.price
+			}
+			c = tr.price
+			volume += tr.qty
+			quoteVolume += tr.quoteQty
+			if !tr.isBuyerMaker {
+				takerVolume += tr.qty
+				takerQuoteVolume += tr.quoteQty
+			}
+			count++
+		}
+		lastPrice = c
+		response = append(response, []interface{}{
+			openTime, formatFloat(o), formatFloat(h), formatFloat(l), formatFloat(c),
+			formatFloat(volume), closeTime, formatFloat(quoteVolume), count,
+			formatFloat(takerVolume), formatFloat(takerQuoteVolume), "0",
+		})
+	}
+	return response, nil
+}
+
+func (o *order) json() map[string]interface{} {
+	return map[string]interface{}{
+		"symbol":              o.symbol,
+		"orderId":             o.orderId,
+		"orderListId":         -1,
+		"clientOrderId":       o.clientOrderId,
+		"price":               formatFloat(o.price),
+		"origQty":             formatFloat(o.origQty),
+		"executedQty":         formatFloat(o.executedQty),
+		"cummulativeQuoteQty": formatFloat(o.quoteQty),
+		"status":              o.status,
+		"timeInForce":         o.timeInForce,
+		"type":                o.orderType,
+		"side":                o.side,
+		"stopPrice":           formatFloat(0),
+		"icebergQty":          formatFloat(0),
+		"time":                o.time,
+		"updateTime":          o.updateTime,
+		"isWorking":           o.status == binanceapi.OrderStatusNew,
+	}
+}
+
+func (s *Server) postOrder(params url.Values) (interface{}, *apiError) {
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	side := binanceapi.OrderSide(params.Get("side"))
+	if side != binanceapi.OrderSideBuy && side != binanceapi.OrderSideSell {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeInvalidSide, "Invalid side.")
+	}
+	orderType := binanceapi.OrderType(params.Get("type"))
+	if orderType != binanceapi.OrderTypeLimit && orderType != binanceapi.OrderTypeMarket {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeInvalidOrderType, "Invalid orderType.")
+	}
+	quantity, err := strconv.ParseFloat(params.Get("quantity"), 64)
+	if err != nil || quantity <= 0 {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeMandatoryParamMissing,
+			"Mandatory parameter 'quantity' was not sent, was empty/null, or malformed.")
+	}
+	price := sym.price
+	if orderType == binanceapi.OrderTypeLimit {
+		price, err = strconv.ParseFloat(params.Get("price"), 64)
+		if err != nil || price <= 0 {
+			return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeMandatoryParamMissing,
+				"Mandatory parameter 'price' was not sent, was empty/null, or malformed.")
+		}
+	}
+
+	// Funds needed from the asset being sold.
+	asset, amount := sym.info.QuoteAsset, quantity*price
+	if side == binanceapi.OrderSideSell {
+		asset, amount = sym.info.BaseAsset, quantity
+	}
+	if s.balance(asset).free < amount {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeNewOrderRejected,
+			"Account has insufficient balance for requested action.")
+	}
+
+	now := s.millis()
+	o := &order{
+		symbol:        sym.info.Symbol,
+		orderId:       s.nextOrderId,
+		clientOrderId: params.Get("newClientOrderId"),
+		price:         price,
+		origQty:       quantity,
+		status:        binanceapi.OrderStatusNew,
+		timeInForce:   binanceapi.TimeInForce(params.Get("timeInForce")),
+		orderType:     orderType,
+		side:          side,
+		time:          now,
+		updateTime:    now,
+	}
+	s.nextOrderId++
+	if o.clientOrderId == "" {
+		o.clientOrderId = randomString(11)
+	}
+
+	fills := []interface{}{}
+	if orderType == binanceapi.OrderTypeMarket {
+		o.price = 0
+		o.executedQty = quantity
+		o.quoteQty = quantity * price
+		o.status = binanceapi.OrderStatusFilled
+		t := &trade{
+			symbol:   o.symbol,
+			id:       s.nextTradeId,
+			orderId:  o.orderId,
+			price:    price,
+			qty:      quantity,
+			quoteQty: quantity * price,
+			time:     now,
+			isBuyer:  side == binanceapi.OrderSideBuy,
+
+			// The market order took liquidity from the book.
+			isBuyerMaker: side == binanceapi.OrderSideSell,
+		}
+		s.nextTradeId++
+		s.trades = append(s.trades, t)
+		if side == binanceapi.OrderSideBuy {
+			s.balance(sym.info.QuoteAsset).free -= t.quoteQty
+			s.balance(sym.info.BaseAsset).free += t.qty
+		} else {
+			s.balance(sym.info.BaseAsset).free -= t.qty
+			s.balance(sym.info.QuoteAsset).free += t.quoteQty
+		}
+		fills = append(fills, map[string]interface{}{
+			"price":           formatFloat(t.price),
+			"qty":             formatFloat(t.qty),
+			"commission":      formatFloat(0),
+			"commissionAsset": sym.info.BaseAsset,
+			"tradeId":         t.id,
+		})
+	} else {
+		s.balance(asset).free -= amount
+		s.balance(asset).locked += amount
+	}
+	s.orders = append(s.orders, o)
+
+	respType := params.Get("newOrderRespType")
+	if respType == "" {
+		respType = "FULL"
+	}
+	if respType == "ACK" {
+		return map[string]interface{}{
+			"symbol":        o.symbol,
+			"orderId":       o.orderId,
+			"orderListId":   -1,
+			"clientOrderId": o.clientOrderId,
+			"transactTime":  now,
+		}, nil
+	}
+	response := o.json()
+	response["transactTime"] = now
+	if respType == "FULL" {
+		response["fills"] = fills
+	}
+	return response, nil
+}
+
+func (s *Server) findOrder(params url.Values) (*order, *apiError) {
+	orderId, _ := strconv.ParseInt(params.Get("orderId"), 10, 64)
+	clientOrderId := params.Get("origClientOrderId")
+	if orderId == 0 && clientOrderId == "" {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeMandatoryParamMissing,
+			"Param 'origClientOrderId' or 'orderId' must be sent, but both were empty/null!")
+	}
+	for _, o := range s.orders {
+		if o.symbol != params.Get("symbol") {
+			continue
+		}
+		if (orderId != 0 && o.orderId == orderId) ||
+			(clientOrderId != "" && o.clientOrderId == clientOrderId) {
+			return o, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) getOrder(params url.Values) (interface{}, *apiError) {
+	if _, apiErr := s.symbol(params); apiErr != nil {
+		return nil, apiErr
+	}
+	o, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if o == nil {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeNoSuchOrder, "Order does not exist.")
+	}
+	return o.json(), nil
+}
+
+func (s *Server) deleteOrder(params url.Values) (interface{}, *apiError) {
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	o, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if o == nil || o.status != binanceapi.OrderStatusNew {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeCancelRejected, "Unknown order sent.")
+	}
+
+	asset, amount := sym.info.QuoteAsset, o.origQty*o.price
+	if o.side == binanceapi.OrderSideSell {
+		asset, amount = sym.info.BaseAsset, o.origQty
+	}
+	s.balance(asset).locked -= amount
+	s.balance(asset).free += amount
+
+	origClientOrderId := o.clientOrderId
+	o.status = binanceapi.OrderStatusCanceled
+	o.updateTime = s.millis()
+	response := o.json()
+	response["origClientOrderId"] = origClientOrderId
+	response["clientOrderId"] = randomString(11)
+	return response, nil
+}
+
+func (s *Server) myTrades(params url.Values) (interface{}, *apiError) {
+	sym, apiErr := s.symbol(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	limit := 500
+	if params.Get("limit") != "" {
+		limit, _ = strconv.Atoi(params.Get("limit"))
+	}
+	fromId := int64(-1)
+	if params.Get("fromId") != "" {
+		fromId, _ = strconv.ParseInt(params.Get("fromId"), 10, 64)
+	}
+	trades := []interface{}{}
+	for _, t := range s.trades {
+		// Trades added with AddTrade are not by the account.
+		if t.symbol != sym.info.Symbol || t.orderId == 0 || t.id < fromId {
+			continue
+		}
+		if len(trades) >= limit {
+			break
+		}
+		trades = append(trades, map[string]interface{}{
+			"symbol":          t.symbol,
+			"id":              t.id,
+			"orderId":         t.orderId,
+			"orderListId":     -1,
+			"price":           formatFloat(t.price),
+			"qty":             formatFloat(t.qty),
+			"quoteQty":        formatFloat(t.quoteQty),
+			"commission":      formatFloat(0),
+			"commissionAsset": sym.info.BaseAsset,
+			"time":            t.time,
+			"isBuyer":         t.isBuyer,
+			"isMaker":         t.isMaker,
+			"isBestMatch":     true,
+		})
+	}
+	return trades, nil
+}
+
+func (s *Server) account(params url.Values) (interface{}, *apiError) {
+	assets := []string{}
+	for asset := range s.balances {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	balances := []interface{}{}
+	for _, asset := range assets {
+		b := s.balances[asset]
+		balances = append(balances, map[string]interface{}{
+			"asset":  asset,
+			"free":   formatFloat(b.free),
+			"locked": formatFloat(b.locked),
+		})
+	}
+	return map[string]interface{}{
+		"makerCommission":  10,
+		"takerCommission":  10,
+		"buyerCommission":  0,
+		"sellerCommission": 0,
+		"canTrade":         true,
+		"canWithdraw":      true,
+		"canDeposit":       true,
+		"updateTime":       s.millis(),
+		"accountType":      "SPOT",
+		"balances":         balances,
+	}, nil
+}
+
+func (s *Server) postUserDataStream(params url.Values) (interface{}, *apiError) {
+	listenKey := randomString(60)
+	s.listenKeys[listenKey] = true
+	return map[string]interface{}{
+		"listenKey": listenKey,
+	}, nil
+}
+
+func (s *Server) putUserDataStream(params url.Values) (interface{}, *apiError) {
+	if !s.listenKeys[params.Get("listenKey")] {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeInvalidListenKey,
+			"This listenKey does not exist.")
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (s *Server) deleteUserDataStream(params url.Values) (interface{}, *apiError) {
+	if !s.listenKeys[params.Get("listenKey")] {
+		return nil, newApiError(http.StatusBadRequest, binanceapi.ErrorCodeInvalidListenKey,
+			"This listenKey does not exist.")
+	}
+	delete(s.listenKeys, params.Get("listenKey"))
+	return map[string]interface{}{}, nil
+}
+
+func randomString(n int) string {
+	buf := make([]byte, (n+1)/2)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)[:n]
+}
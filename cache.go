@@ -0,0 +1,235 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTtls are reasonable cache lifetimes for public endpoints whose
+// data changes rarely.
+var DefaultCacheTtls = map[string]time.Duration{
+	"/api/v1/exchangeInfo": time.Minute,
+	"/api/v3/exchangeInfo": time.Minute,
+}
+
+// How long a request shared by coalesced callers may take, as it is not
+// bound by the context of any one caller.
+const cacheFetchTimeout = time.Minute
+
+// ResponseCache caches the responses of unsigned GET requests for a
+// per-endpoint time to live. Concurrent identical requests are coalesced
+// into one request to the server. Responses are kept per base URL, so a
+// cache may be shared between clients of different environments.
+type ResponseCache struct {
+	ttls map[string]time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry
+	inflight map[string]*cacheCall
+}
+
+type cacheEntry struct {
+	endpoint   string
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+type cacheCall struct {
+	done  chan struct{}
+	entry *cacheEntry
+	err   error
+}
+
+// NewResponseCache creates a cache for the endpoints in ttls, which maps an
+// endpoint path such as /api/v3/ticker/price to how long its responses are
+// kept. Other endpoints are not cached.
+func NewResponseCache(ttls map[string]time.Duration) *ResponseCache {
+	cache := &ResponseCache{
+		ttls:     map[string]time.Duration{},
+		entries:  map[string]*cacheEntry{},
+		inflight: map[string]*cacheCall{},
+	}
+	for endpoint, ttl := range ttls {
+		cache.ttls[endpoint] = ttl
+	}
+	return cache
+}
+
+// WithCache caches the responses of unsigned GET requests in cache. A cache
+// may be shared between clients.
+func WithCache(cache *ResponseCache) RestClientOption {
+	return func(c *RestClient) {
+		c.cache = cache
+	}
+}
+
+// Invalidate removes all cached responses of endpoint.
+func (rc *ResponseCache) Invalidate(endpoint string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key, entry := range rc.entries {
+		if entry.endpoint == endpoint {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+// InvalidateAll removes all cached responses.
+func (rc *ResponseCache) InvalidateAll() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = map[string]*cacheEntry{}
+}
+
+func (rc *ResponseCache) cacheable(r *restRequest) bool {
+	if r.method != "GET" || r.security != SecurityTypeNone {
+		return false
+	}
+	return rc.ttls[r.endpoint] > 0
+}
+
+// get returns the cached response for r sent to one of the hosts in
+// baseUrls, or calls fetch to get it. Only one fetch is done at a time for
+// the same request, concurrent callers wait for and share its result. The
+// fetch is not cancelled with the context of the caller that started it, but
+// each caller stops waiting when its own ctx is done.
+func (rc *ResponseCache) get(ctx context.Context, baseUrls []string, r *restRequest,
+	fetch func(ctx context.Context) (*http.Response, error)) (*http.Response, error) {
+	key := fmt.Sprintf("%s %s%s?%s", r.method, strings.Join(baseUrls, ","),
+		r.endpoint, encodeParams(r.params))
+
+	rc.mu.Lock()
+	if entry, ok := rc.entries[key]; ok {
+		if time.Now().Before(entry.expires) {
+			rc.mu.Unlock()
+			return entry.response(), nil
+		}
+		delete(rc.entries, key)
+	}
+	call, ok := rc.inflight[key]
+	if !ok {
+		call = &cacheCall{done: make(chan struct{})}
+		rc.inflight[key] = call
+		go rc.fetchShared(detachedContext{ctx}, key, call, r, fetch)
+	}
+	rc.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	return call.entry.response(), nil
+}
+
+// fetchShared does the fetch of call for all its callers and caches the
+// result.
+func (rc *ResponseCache) fetchShared(ctx context.Context, key string, call *cacheCall, r *restRequest,
+	fetch func(ctx context.Context) (*http.Response, error)) {
+	ctx, cancel := context.WithTimeout(ctx, cacheFetchTimeout)
+	defer cancel()
+	call.entry, call.err = rc.fetch(r, func() (*http.Response, error) {
+		return fetch(ctx)
+	})
+
+	rc.mu.Lock()
+	delete(rc.inflight, key)
+	if call.err == nil && call.entry.statusCode < 300 {
+		rc.entries[key] = call.entry
+	}
+	rc.mu.Unlock()
+	close(call.done)
+}
+
+func (rc *ResponseCache) fetch(r *restRequest, fetch func() (*http.Response, error)) (*cacheEntry, error) {
+	response, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponse(response)
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &cacheEntry{
+		endpoint:   r.endpoint,
+		statusCode: response.StatusCode,
+		header:     response.Header,
+		body:       body,
+		expires:    time.Now().Add(rc.ttls[r.endpoint]),
+	}, nil
+}
+
+// detachedContext keeps the values of a context but not its deadline or
+// cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// response returns a new response for the entry, with its own body reader.
+func (e *cacheEntry) response() *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.statusCode, http.StatusText(e.statusCode)),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+	}
+}
+
+// InvalidateCache removes the cached responses of endpoint, if the client
+// has a cache.
+func (c *RestClient) InvalidateCache(endpoint string) {
+	if c.cache != nil {
+		c.cache.Invalidate(endpoint)
+	}
+}
@@ -0,0 +1,226 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func newCache(ttl time.Duration) *binanceapi.ResponseCache {
+	return binanceapi.NewResponseCache(map[string]time.Duration{
+		"/api/v3/ticker/price":     ttl,
+		"/api/v3/account":          ttl,
+		"/api/v3/historicalTrades": ttl,
+	})
+}
+
+func TestCacheExpiry(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client(binanceapi.WithCache(newCache(100 * time.Millisecond)))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countRequests(server, "/api/v3/ticker/price"); n != 1 {
+		t.Errorf("expected 1 request before expiry, got %d", n)
+	}
+
+	// Different parameters are cached separately.
+	if _, err := client.GetPriceTicker("ETHUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(server, "/api/v3/ticker/price"); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(server, "/api/v3/ticker/price"); n != 3 {
+		t.Errorf("expected 3 requests after expiry, got %d", n)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client(binanceapi.WithCache(newCache(time.Minute)))
+
+	if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	server.SetPrice("BTCUSDT", 60000)
+	client.InvalidateCache("/api/v3/ticker/price")
+	ticker, err := client.GetPriceTicker("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticker.Price != 60000 {
+		t.Errorf("expected the price after invalidation, got %v", ticker.Price)
+	}
+	if n := countRequests(server, "/api/v3/ticker/price"); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestCacheOnlyUnsigned(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	server.AddTrade("BTCUSDT", 50000, 0.1, true, time.Now())
+	client := server.Client(binanceapi.WithCache(newCache(time.Minute)))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetAccount(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.GetHistoricalTrades("BTCUSDT", 10, -1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{"/api/v3/account", "/api/v3/historicalTrades"} {
+		if n := countRequests(server, path); n != 2 {
+			t.Errorf("%s: expected 2 requests, got %d", path, n)
+		}
+	}
+}
+
+func TestCachePerBaseUrl(t *testing.T) {
+	mainnet := binanceapitest.NewServer()
+	defer mainnet.Close()
+	testnet := binanceapitest.NewServer()
+	defer testnet.Close()
+	testnet.SetPrice("BTCUSDT", 1)
+
+	client := mainnet.Client(binanceapi.WithCache(newCache(time.Minute)))
+	if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	ticker, err := client.With(binanceapi.WithBaseUrl(testnet.URL)).GetPriceTicker("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticker.Price != 1 {
+		t.Errorf("got the price of another base URL: %v", ticker.Price)
+	}
+}
+
+// newBlockingServer returns a server answering price tickers only once
+// release is closed, and the number of requests it received.
+func newBlockingServer() (server *httptest.Server, release chan struct{}, requests *int32) {
+	release = make(chan struct{})
+	requests = new(int32)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		<-release
+		w.Write([]byte(`{"symbol":"BTCUSDT","price":"50000.00"}`))
+	}))
+	return server, release, requests
+}
+
+func TestCacheCoalescing(t *testing.T) {
+	server, release, requests := newBlockingServer()
+	defer server.Close()
+	client := binanceapi.NewRestClient(binanceapi.WithBaseUrl(server.URL),
+		binanceapi.WithCache(newCache(time.Minute)))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
+
+func TestCacheCancellation(t *testing.T) {
+	server, release, requests := newBlockingServer()
+	defer server.Close()
+	client := binanceapi.NewRestClient(binanceapi.WithBaseUrl(server.URL),
+		binanceapi.WithCache(newCache(time.Minute)))
+
+	// The first caller gives up, the second times out, and the third still
+	// gets the response of the shared request.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.GetPriceTickerCtx(ctx, "BTCUSDT")
+		first <- err
+	}()
+	for atomic.LoadInt32(requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	third := make(chan error, 1)
+	go func() {
+		_, err := client.GetPriceTicker("BTCUSDT")
+		third <- err
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the first caller to be cancelled, got %v", err)
+	}
+
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelTimeout()
+	if _, err := client.GetPriceTickerCtx(timeout, "BTCUSDT"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the second caller to time out, got %v", err)
+	}
+
+	close(release)
+	if err := <-third; err != nil {
+		t.Errorf("expected the third caller to succeed, got %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
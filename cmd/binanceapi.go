@@ -24,9 +24,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/crankykernel/binanceapi-go"
 	"os"
+	"strings"
+	"time"
 )
 
 type Command struct {
@@ -42,6 +45,11 @@ func main() {
 			help:    "Connect to a websocket stream",
 			handler: streamHandler,
 		},
+		"sign": Command{
+			name:    "sign",
+			help:    "Sign key=value parameters with BINANCE_API_KEY and BINANCE_API_SECRET",
+			handler: signHandler,
+		},
 	}
 
 	if len(os.Args) < 2 {
@@ -73,3 +81,33 @@ func streamHandler(args []string) {
 		fmt.Printf("%s\n", string(body))
 	}
 }
+
+// signHandler prints the signed query string of the key=value parameters in
+// args, adding a timestamp if there is none.
+func signHandler(args []string) {
+	credentials, err := binanceapi.NewEnvCredentials().Credentials(context.Background())
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		return
+	}
+
+	params := map[string]interface{}{}
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i < 0 {
+			fmt.Printf("error: expected key=value: %s\n", arg)
+			return
+		}
+		params[arg[:i]] = arg[i+1:]
+	}
+	if _, ok := params["timestamp"]; !ok {
+		params["timestamp"] = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+
+	signed, err := binanceapi.SignParams(credentials.Signer, params)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		return
+	}
+	fmt.Printf("%s\n", signed.Encode())
+}
@@ -50,13 +50,11 @@ func (b *CombinedStreamBuilder) SubscribeAllMarketTicker() *CombinedStreamBuilde
 }
 
 func (b *CombinedStreamBuilder) Connect() (*Stream, error) {
-	endpoint := fmt.Sprintf("stream?streams=%s", strings.Join(b.streams, "/"))
-	stream, err := OpenStream(endpoint)
-	if err != nil {
-		return nil, err
-	}
-	stream.Type = STREAM_TYPE_COMBINED
-	return stream, nil
+	return defaultStreamEnvironment().OpenCombinedStream(b)
+}
+
+func (b *CombinedStreamBuilder) endpoint() string {
+	return fmt.Sprintf("stream?streams=%s", strings.Join(b.streams, "/"))
 }
 
 // Stream name: <symbol>@aggTrade.
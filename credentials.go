@@ -0,0 +1,279 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials are the API key and the signer for its secret or private key.
+type Credentials struct {
+	ApiKey string
+	Signer Signer
+}
+
+func (c Credentials) String() string {
+	return fmt.Sprintf("Credentials{ApiKey: %s}", RedactSecret(c.ApiKey))
+}
+
+func (c Credentials) GoString() string {
+	return c.String()
+}
+
+// CredentialProvider supplies the credentials for a request. It is consulted
+// for every request that needs the API key, so a provider may rotate
+// credentials without the client being recreated.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// WithCredentialProvider sets the provider of the API key and signer.
+func WithCredentialProvider(provider CredentialProvider) RestClientOption {
+	return func(c *RestClient) {
+		c.credentials = provider
+	}
+}
+
+// StaticCredentials always provides the same credentials.
+type StaticCredentials struct {
+	credentials Credentials
+}
+
+func NewStaticCredentials(apiKey string, signer Signer) *StaticCredentials {
+	return &StaticCredentials{
+		credentials: Credentials{
+			ApiKey: apiKey,
+			Signer: signer,
+		},
+	}
+}
+
+func (p *StaticCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	return p.credentials, nil
+}
+
+func (p *StaticCredentials) String() string {
+	return fmt.Sprintf("StaticCredentials{%v}", p.credentials)
+}
+
+func (p *StaticCredentials) GoString() string {
+	return p.String()
+}
+
+// EnvCredentials reads the API key and secret from environment variables
+// on every request. The secret may be an HMAC secret or a PEM encoded RSA or
+// Ed25519 private key.
+type EnvCredentials struct {
+	KeyVar    string
+	SecretVar string
+}
+
+// NewEnvCredentials reads BINANCE_API_KEY and BINANCE_API_SECRET.
+func NewEnvCredentials() *EnvCredentials {
+	return &EnvCredentials{
+		KeyVar:    "BINANCE_API_KEY",
+		SecretVar: "BINANCE_API_SECRET",
+	}
+}
+
+func (p *EnvCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	key := os.Getenv(p.KeyVar)
+	if key == "" {
+		return Credentials{}, fmt.Errorf("environment variable %s not set", p.KeyVar)
+	}
+	secret := os.Getenv(p.SecretVar)
+	if secret == "" {
+		return Credentials{}, fmt.Errorf("environment variable %s not set", p.SecretVar)
+	}
+	return newCredentials(key, secret)
+}
+
+func (p *EnvCredentials) String() string {
+	return fmt.Sprintf("EnvCredentials{KeyVar: %s, SecretVar: %s}", p.KeyVar, p.SecretVar)
+}
+
+func (p *EnvCredentials) GoString() string {
+	return p.String()
+}
+
+// credentialsDocument is the JSON format read by FileCredentials and
+// CommandCredentials. Exactly one of secret or privateKey should be set.
+type credentialsDocument struct {
+	ApiKey     string `json:"apiKey"`
+	Secret     string `json:"secret"`
+	PrivateKey string `json:"privateKey"`
+}
+
+func (d *credentialsDocument) credentials() (Credentials, error) {
+	if d.ApiKey == "" {
+		return Credentials{}, fmt.Errorf("apiKey missing")
+	}
+	if d.PrivateKey != "" {
+		return newCredentials(d.ApiKey, d.PrivateKey)
+	}
+	if d.Secret != "" {
+		return newCredentials(d.ApiKey, d.Secret)
+	}
+	return Credentials{}, fmt.Errorf("secret or privateKey missing")
+}
+
+// newCredentials creates credentials for an HMAC secret, or for a private key
+// if secret is PEM encoded.
+func newCredentials(apiKey string, secret string) (Credentials, error) {
+	if strings.HasPrefix(strings.TrimSpace(secret), "-----BEGIN") {
+		signer, err := NewSignerFromPem([]byte(secret))
+		if err != nil {
+			return Credentials{}, err
+		}
+		return Credentials{ApiKey: apiKey, Signer: signer}, nil
+	}
+	return Credentials{ApiKey: apiKey, Signer: NewHmacSigner(secret)}, nil
+}
+
+// FileCredentials reads credentials from a JSON file with the fields apiKey
+// and secret or privateKey. The file is re-read when it changes and must not
+// be accessible by group or others.
+type FileCredentials struct {
+	path string
+
+	mu          sync.Mutex
+	modTime     time.Time
+	credentials Credentials
+}
+
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{
+		path: path,
+	}
+}
+
+func (p *FileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return Credentials{}, fmt.Errorf("credentials file %s has permissions %v, must not be accessible by group or others",
+			p.path, info.Mode().Perm())
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if info.ModTime().Equal(p.modTime) {
+		return p.credentials, nil
+	}
+
+	buf, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var document credentialsDocument
+	if err := json.Unmarshal(buf, &document); err != nil {
+		return Credentials{}, fmt.Errorf("failed to decode credentials file %s: %v", p.path, err)
+	}
+	credentials, err := document.credentials()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("invalid credentials file %s: %v", p.path, err)
+	}
+	p.credentials = credentials
+	p.modTime = info.ModTime()
+	return credentials, nil
+}
+
+func (p *FileCredentials) String() string {
+	return fmt.Sprintf("FileCredentials{path: %s}", p.path)
+}
+
+func (p *FileCredentials) GoString() string {
+	return p.String()
+}
+
+// CommandCredentials runs a helper command that prints credentials as JSON
+// in the format read by FileCredentials. The result is cached for TTL.
+type CommandCredentials struct {
+	Command string
+	Args    []string
+	TTL     time.Duration
+
+	mu          sync.Mutex
+	expires     time.Time
+	credentials Credentials
+}
+
+func NewCommandCredentials(command string, args ...string) *CommandCredentials {
+	return &CommandCredentials{
+		Command: command,
+		Args:    args,
+		TTL:     5 * time.Minute,
+	}
+}
+
+func (p *CommandCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Now().Before(p.expires) {
+		return p.credentials, nil
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return Credentials{}, fmt.Errorf("credentials command %s failed: %v", p.Command, err)
+	}
+
+	// The output is not included in errors as it holds the secret.
+	var document credentialsDocument
+	if err := json.Unmarshal(stdout.Bytes(), &document); err != nil {
+		return Credentials{}, fmt.Errorf("failed to decode output of credentials command %s", p.Command)
+	}
+	credentials, err := document.credentials()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("invalid output of credentials command %s: %v", p.Command, err)
+	}
+	p.credentials = credentials
+	p.expires = time.Now().Add(p.TTL)
+	return credentials, nil
+}
+
+// String does not include the arguments of the command as they may hold
+// a secret.
+func (p *CommandCredentials) String() string {
+	return fmt.Sprintf("CommandCredentials{Command: %s}", p.Command)
+}
+
+func (p *CommandCredentials) GoString() string {
+	return p.String()
+}
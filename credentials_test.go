@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+)
+
+const (
+	testApiKey = "vmPUZE6mv9SD5VNHk4HlWFsOr6aKE2zvsw0MuIgwCIPy6utIco14y7Ju91duEh8A"
+	testSecret = "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+)
+
+func TestCredentialsNotFormatted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "binanceapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "credentials.json")
+	document := fmt.Sprintf(`{"apiKey": %q, "secret": %q}`, testApiKey, testSecret)
+	if err := ioutil.WriteFile(path, []byte(document), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("BINANCEAPI_TEST_KEY", testApiKey)
+	os.Setenv("BINANCEAPI_TEST_SECRET", testSecret)
+	defer os.Unsetenv("BINANCEAPI_TEST_KEY")
+	defer os.Unsetenv("BINANCEAPI_TEST_SECRET")
+
+	providers := []binanceapi.CredentialProvider{
+		binanceapi.NewStaticCredentials(testApiKey, binanceapi.NewHmacSigner(testSecret)),
+		&binanceapi.EnvCredentials{KeyVar: "BINANCEAPI_TEST_KEY", SecretVar: "BINANCEAPI_TEST_SECRET"},
+		binanceapi.NewFileCredentials(path),
+		binanceapi.NewCommandCredentials("cat", path),
+	}
+	for _, provider := range providers {
+		// Load the credentials so any cached copy is formatted too.
+		credentials, err := provider.Credentials(context.Background())
+		if err != nil {
+			t.Fatalf("%T: %v", provider, err)
+		}
+		if credentials.ApiKey != testApiKey {
+			t.Fatalf("%T: unexpected API key %s", provider, credentials.ApiKey)
+		}
+
+		client := binanceapi.NewRestClient(binanceapi.WithCredentialProvider(provider))
+		for _, value := range []interface{}{client, provider, credentials} {
+			for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+				formatted := fmt.Sprintf(format, value)
+				if strings.Contains(formatted, testApiKey) || strings.Contains(formatted, testSecret) {
+					t.Errorf("%T formatted with %s leaks credentials: %s", value, format, formatted)
+				}
+			}
+		}
+	}
+}
@@ -24,6 +24,7 @@
 package binanceapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 )
@@ -35,3 +36,11 @@ func decodeStringFloat64(v interface{}) (val float64, err error) {
 	}
 	return strconv.ParseFloat(s, 64)
 }
+
+func decodeNumberInt64(v interface{}) (val int64, err error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return val, fmt.Errorf("value is not a number")
+	}
+	return n.Int64()
+}
@@ -0,0 +1,200 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment is a set of Binance endpoints that belong together, such as
+// production or the spot testnet.
+type Environment struct {
+	Name string
+
+	// Root URL of the REST API.
+	RestUrl string
+
+	// Root URL of the market data websocket streams.
+	StreamUrl string
+
+	// Root URL of the user data websocket streams.
+	UserStreamUrl string
+}
+
+var EnvironmentProduction = Environment{
+	Name:          "production",
+	RestUrl:       API_ROOT,
+	StreamUrl:     "wss://stream.binance.com:9443",
+	UserStreamUrl: "wss://stream.binance.com:9443",
+}
+
+var EnvironmentTestnet = Environment{
+	Name:          "testnet",
+	RestUrl:       "https://testnet.binance.vision",
+	StreamUrl:     "wss://stream.testnet.binance.vision",
+	UserStreamUrl: "wss://stream.testnet.binance.vision",
+}
+
+// The environment used by new clients and the package level stream
+// openers. Selected with BINANCE_ENVIRONMENT, which may be "production" or
+// "testnet".
+var defaultEnvironment = environmentFromEnv()
+
+// Guards defaultEnvironment, defaultBaseUrl and STREAM_URL after package
+// initialisation.
+var defaultsMu sync.RWMutex
+
+func environmentFromEnv() Environment {
+	name := os.Getenv("BINANCE_ENVIRONMENT")
+	if name == "" {
+		return EnvironmentProduction
+	}
+	env, err := LookupEnvironment(name)
+	if err != nil {
+		log.Printf("error: %v, using production", err)
+		return EnvironmentProduction
+	}
+	log.Printf("Using Binance environment from environment: %s", env.Name)
+	return env
+}
+
+// LookupEnvironment returns the predefined environment with the given name.
+func LookupEnvironment(name string) (Environment, error) {
+	switch strings.ToLower(name) {
+	case EnvironmentProduction.Name:
+		return EnvironmentProduction, nil
+	case EnvironmentTestnet.Name:
+		return EnvironmentTestnet, nil
+	}
+	return Environment{}, fmt.Errorf("unknown Binance environment: %s", name)
+}
+
+// SetDefaultEnvironment switches clients created after the call, and the
+// package level stream openers, to env. It is safe to call while other
+// goroutines create clients or open streams.
+func SetDefaultEnvironment(env Environment) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultEnvironment = env
+	defaultBaseUrl = strings.TrimSuffix(env.RestUrl, "/")
+	STREAM_URL = env.StreamUrl
+}
+
+// DefaultEnvironment returns the environment set with SetDefaultEnvironment
+// or BINANCE_ENVIRONMENT.
+func DefaultEnvironment() Environment {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return defaultEnvironment
+}
+
+// defaultUrls returns the default environment along with the REST and
+// stream URLs to use for it, which BINANCE_API_URL and writes to the
+// deprecated STREAM_URL may override.
+func defaultUrls() (env Environment, restUrl string, streamUrl string) {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return defaultEnvironment, defaultBaseUrl, STREAM_URL
+}
+
+// defaultStreamEnvironment returns the environment the package level stream
+// openers connect to.
+func defaultStreamEnvironment() Environment {
+	env, _, streamUrl := defaultUrls()
+	env.StreamUrl = streamUrl
+	return env
+}
+
+// WithEnvironment points the client at the REST API of env. Streams opened
+// through the client with OpenUserDataStream use the same environment.
+func WithEnvironment(env Environment) RestClientOption {
+	return func(c *RestClient) {
+		c.environment = env
+		c.hosts = newHostSelector([]string{env.RestUrl})
+	}
+}
+
+// Environment returns the environment of the client.
+func (c *RestClient) Environment() Environment {
+	return c.environment
+}
+
+// OpenStream opens a raw stream, eg. stream?streams=..., in env.
+func (env Environment) OpenStream(stream string) (*Stream, error) {
+	return openStreamUrl(fmt.Sprintf("%s/%s", env.StreamUrl, stream))
+}
+
+// OpenSingleStream opens a single named stream in env.
+func (env Environment) OpenSingleStream(stream string) (*Stream, error) {
+	return openStreamUrl(fmt.Sprintf("%s/ws/%s", env.StreamUrl, stream))
+}
+
+// OpenPartialBookDepthStream opens the partial book depth stream of symbol
+// with depth levels, 5, 10 or 20, in env.
+func (env Environment) OpenPartialBookDepthStream(symbol string, depth int) (*Stream, error) {
+	stream, err := env.OpenSingleStream(fmt.Sprintf("%s@depth%d", strings.ToLower(symbol), depth))
+	if err != nil {
+		return nil, err
+	}
+	stream.Type = STREAM_TYPE_PARTIAL_BOOK
+	return stream, nil
+}
+
+// OpenAllMarketTickerStream opens the 24 hour ticker stream of all symbols
+// in env.
+func (env Environment) OpenAllMarketTickerStream() (*Stream, error) {
+	stream, err := env.OpenSingleStream("!ticker@arr")
+	if err != nil {
+		return nil, err
+	}
+	stream.Type = STREAM_TYPE_ALL_MARKET_TICKER
+	return stream, nil
+}
+
+// OpenCombinedStream opens the streams subscribed to in builder as a single
+// combined stream in env.
+func (env Environment) OpenCombinedStream(builder *CombinedStreamBuilder) (*Stream, error) {
+	stream, err := env.OpenStream(builder.endpoint())
+	if err != nil {
+		return nil, err
+	}
+	stream.Type = STREAM_TYPE_COMBINED
+	return stream, nil
+}
+
+// OpenUserDataStream opens the user data stream for a listen key obtained
+// with GetUserDataStream in env.
+func (env Environment) OpenUserDataStream(listenKey string) (*Stream, error) {
+	return openStreamUrl(fmt.Sprintf("%s/ws/%s", env.UserStreamUrl, listenKey))
+}
+
+// OpenUserDataStream opens the user data stream for a listen key in the
+// environment of the client.
+func (c *RestClient) OpenUserDataStream(listenKey string) (*Stream, error) {
+	return c.environment.OpenUserDataStream(listenKey)
+}
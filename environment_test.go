@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/gorilla/websocket"
+)
+
+func TestEnvironmentStreamOpeners(t *testing.T) {
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.RequestURI()
+		upgrader := websocket.Upgrader{}
+		if conn, err := upgrader.Upgrade(w, r, nil); err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	env := binanceapi.Environment{
+		Name:      "test",
+		StreamUrl: "ws" + strings.TrimPrefix(server.URL, "http"),
+	}
+	tests := []struct {
+		open       func() (*binanceapi.Stream, error)
+		path       string
+		streamType binanceapi.StreamType
+	}{
+		{func() (*binanceapi.Stream, error) { return env.OpenPartialBookDepthStream("BTCUSDT", 5) },
+			"/ws/btcusdt@depth5", binanceapi.STREAM_TYPE_PARTIAL_BOOK},
+		{env.OpenAllMarketTickerStream, "/ws/!ticker@arr", binanceapi.STREAM_TYPE_ALL_MARKET_TICKER},
+		{func() (*binanceapi.Stream, error) {
+			return env.OpenCombinedStream(binanceapi.NewCombinedStreamBuilder().
+				SubscribeAggTrade("BTCUSDT").SubscribeAllMarketTicker())
+		}, "/stream?streams=btcusdt@aggTrade/!ticker@arr", binanceapi.STREAM_TYPE_COMBINED},
+	}
+	for _, test := range tests {
+		stream, err := test.open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream.Close()
+		if stream.Type != test.streamType {
+			t.Errorf("%s: expected stream type %d, got %d", test.path, test.streamType, stream.Type)
+		}
+		if path := <-paths; path != test.path {
+			t.Errorf("expected %s, got %s", test.path, path)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorCode is the numeric code returned by Binance in the body of a failed
+// request, for example {"code":-2010,"msg":"..."}.
+type ErrorCode int
+
+// General server or network issues.
+const (
+	ErrorCodeUnknown            ErrorCode = -1000
+	ErrorCodeDisconnected       ErrorCode = -1001
+	ErrorCodeUnauthorized       ErrorCode = -1002
+	ErrorCodeTooManyRequests    ErrorCode = -1003
+	ErrorCodeUnexpectedResponse ErrorCode = -1006
+	ErrorCodeTimeout            ErrorCode = -1007
+	ErrorCodeServerBusy         ErrorCode = -1008
+	ErrorCodeInvalidMessage     ErrorCode = -1013
+	ErrorCodeUnknownOrderComp   ErrorCode = -1014
+	ErrorCodeTooManyOrders      ErrorCode = -1015
+	ErrorCodeServiceShutdown    ErrorCode = -1016
+	ErrorCodeUnsupportedOp      ErrorCode = -1020
+	ErrorCodeInvalidTimestamp   ErrorCode = -1021
+	ErrorCodeInvalidSignature   ErrorCode = -1022
+)
+
+// Request issues.
+const (
+	ErrorCodeIllegalChars          ErrorCode = -1100
+	ErrorCodeTooManyParameters     ErrorCode = -1101
+	ErrorCodeMandatoryParamMissing ErrorCode = -1102
+	ErrorCodeUnknownParam          ErrorCode = -1103
+	ErrorCodeUnreadParameters      ErrorCode = -1104
+	ErrorCodeParamEmpty            ErrorCode = -1105
+	ErrorCodeParamNotRequired      ErrorCode = -1106
+	ErrorCodeBadPrecision          ErrorCode = -1111
+	ErrorCodeNoDepth               ErrorCode = -1112
+	ErrorCodeTifNotRequired        ErrorCode = -1114
+	ErrorCodeInvalidTif            ErrorCode = -1115
+	ErrorCodeInvalidOrderType      ErrorCode = -1116
+	ErrorCodeInvalidSide           ErrorCode = -1117
+	ErrorCodeEmptyNewClientOrderId ErrorCode = -1118
+	ErrorCodeEmptyOrigClientId     ErrorCode = -1119
+	ErrorCodeBadInterval           ErrorCode = -1120
+	ErrorCodeBadSymbol             ErrorCode = -1121
+	ErrorCodeInvalidListenKey      ErrorCode = -1125
+	ErrorCodeMoreThanXxHours       ErrorCode = -1127
+	ErrorCodeOptionalParamsBad     ErrorCode = -1128
+	ErrorCodeInvalidParameter      ErrorCode = -1130
+	ErrorCodeBadRecvWindow         ErrorCode = -1131
+)
+
+// Order and API key issues.
+const (
+	ErrorCodeNewOrderRejected ErrorCode = -2010
+	ErrorCodeCancelRejected   ErrorCode = -2011
+	ErrorCodeNoSuchOrder      ErrorCode = -2013
+	ErrorCodeBadApiKeyFormat  ErrorCode = -2014
+	ErrorCodeRejectedApiKey   ErrorCode = -2015
+)
+
+// Message sent by Binance with -2011 when the order to cancel does not exist.
+const unknownOrderMessage = "Unknown order sent."
+
+// RestApiError is returned when Binance responds to a REST request with an
+// error status. Use errors.As or the Is* helpers to inspect it.
+type RestApiError struct {
+	StatusCode int
+	Code       ErrorCode `json:"code"`
+	Message    string    `json:"msg"`
+	Body       []byte
+
+	// RetryAfter is the value of the Retry-After header sent with 429 and
+	// 418 responses, or 0 if not present.
+	RetryAfter time.Duration
+}
+
+func NewRestApiErrorFromResponse(r *http.Response) *RestApiError {
+	body, _ := ioutil.ReadAll(r.Body)
+	e := &RestApiError{
+		StatusCode: r.StatusCode,
+		Body:       body,
+	}
+	// Not all error responses carry a JSON body, for example a WAF rejection,
+	// so a decode failure leaves only the status and raw body.
+	_ = json.Unmarshal(body, e)
+	if retryAfter := r.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+			e.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return e
+}
+
+func (e *RestApiError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("binance api error %d: %s", e.Code, e.Message)
+	}
+	if len(e.Body) > 0 {
+		return fmt.Sprintf("binance api error: %d %s: %s",
+			e.StatusCode, http.StatusText(e.StatusCode), string(e.Body))
+	}
+	return fmt.Sprintf("binance api error: %d %s",
+		e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func asRestApiError(err error) (*RestApiError, bool) {
+	var apiErr *RestApiError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsErrorCode returns true if err is a RestApiError with the given code.
+func IsErrorCode(err error, code ErrorCode) bool {
+	apiErr, ok := asRestApiError(err)
+	return ok && apiErr.Code == code
+}
+
+// IsUnknownOrder returns true if the order queried or cancelled does not
+// exist.
+func IsUnknownOrder(err error) bool {
+	apiErr, ok := asRestApiError(err)
+	if !ok {
+		return false
+	}
+	switch apiErr.Code {
+	case ErrorCodeNoSuchOrder:
+		return true
+	case ErrorCodeCancelRejected:
+		return apiErr.Message == unknownOrderMessage
+	}
+	return false
+}
+
+// IsInsufficientBalance returns true if an order was rejected as the account
+// does not have the balance to cover it.
+func IsInsufficientBalance(err error) bool {
+	apiErr, ok := asRestApiError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.Code == ErrorCodeNewOrderRejected &&
+		strings.Contains(strings.ToLower(apiErr.Message), "insufficient balance")
+}
+
+// IsTimestampOutsideRecvWindow returns true if the request timestamp was
+// ahead of the server time or older than the recvWindow.
+func IsTimestampOutsideRecvWindow(err error) bool {
+	return IsErrorCode(err, ErrorCodeInvalidTimestamp)
+}
+
+// IsInvalidSignature returns true if the server rejected the signature of a
+// request, usually as it was made with the wrong secret.
+func IsInvalidSignature(err error) bool {
+	return IsErrorCode(err, ErrorCodeInvalidSignature)
+}
+
+// IsRateLimited returns true if the request was rejected for exceeding a
+// request weight or order rate limit, either by the server or locally by a
+// client in RateLimitModeFailFast.
+func IsRateLimited(err error) bool {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return true
+	}
+	apiErr, ok := asRestApiError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests ||
+		apiErr.Code == ErrorCodeTooManyRequests ||
+		apiErr.Code == ErrorCodeTooManyOrders
+}
+
+// IsIPBanned returns true if the client IP has been banned for continuing to
+// send requests after being rate limited. The ban duration is available in
+// RetryAfter.
+func IsIPBanned(err error) bool {
+	apiErr, ok := asRestApiError(err)
+	return ok && apiErr.StatusCode == http.StatusTeapot
+}
@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+)
+
+func newApiError(statusCode int, header http.Header, body string) *binanceapi.RestApiError {
+	recorder := httptest.NewRecorder()
+	for key, values := range header {
+		recorder.Header()[key] = values
+	}
+	recorder.WriteHeader(statusCode)
+	recorder.WriteString(body)
+	return binanceapi.NewRestApiErrorFromResponse(recorder.Result())
+}
+
+func TestErrorHelpers(t *testing.T) {
+	type helper struct {
+		name string
+		is   func(error) bool
+	}
+	var (
+		unknownOrder        = helper{"IsUnknownOrder", binanceapi.IsUnknownOrder}
+		insufficientBalance = helper{"IsInsufficientBalance", binanceapi.IsInsufficientBalance}
+		timestamp           = helper{"IsTimestampOutsideRecvWindow", binanceapi.IsTimestampOutsideRecvWindow}
+		invalidSignature    = helper{"IsInvalidSignature", binanceapi.IsInvalidSignature}
+		rateLimited         = helper{"IsRateLimited", binanceapi.IsRateLimited}
+		ipBanned            = helper{"IsIPBanned", binanceapi.IsIPBanned}
+	)
+	helpers := []helper{unknownOrder, insufficientBalance, timestamp, invalidSignature, rateLimited, ipBanned}
+
+	retryAfter := http.Header{"Retry-After": []string{"60"}}
+	tests := []struct {
+		err      error
+		expected []helper
+	}{
+		{newApiError(400, nil, `{"code":-2013,"msg":"Order does not exist."}`), []helper{unknownOrder}},
+		{newApiError(400, nil, `{"code":-2011,"msg":"Unknown order sent."}`), []helper{unknownOrder}},
+		{newApiError(400, nil, `{"code":-2011,"msg":"Duplicate order sent."}`), nil},
+		{newApiError(400, nil, `{"code":-2010,"msg":"Account has insufficient balance for requested action."}`),
+			[]helper{insufficientBalance}},
+		{newApiError(400, nil, `{"code":-2010,"msg":"Order would immediately match and take."}`), nil},
+		{newApiError(400, nil, `{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`),
+			[]helper{timestamp}},
+		{newApiError(400, nil, `{"code":-1022,"msg":"Signature for this request is not valid."}`),
+			[]helper{invalidSignature}},
+		{newApiError(429, retryAfter, `{"code":-1003,"msg":"Too many requests."}`), []helper{rateLimited}},
+		{newApiError(400, nil, `{"code":-1015,"msg":"Too many new orders."}`), []helper{rateLimited}},
+		{&binanceapi.RateLimitError{RateLimitType: "REQUEST_WEIGHT"}, []helper{rateLimited}},
+		{newApiError(418, retryAfter, `{"code":-1003,"msg":"Way too many requests; IP banned."}`),
+			[]helper{rateLimited, ipBanned}},
+		{newApiError(403, nil, "<html><body>Forbidden</body></html>"), nil},
+		{fmt.Errorf("placing order: %w",
+			newApiError(400, nil, `{"code":-2013,"msg":"Order does not exist."}`)), []helper{unknownOrder}},
+		{fmt.Errorf("some other error"), nil},
+		{nil, nil},
+	}
+	for _, test := range tests {
+		for _, h := range helpers {
+			expected := false
+			for _, e := range test.expected {
+				if e.name == h.name {
+					expected = true
+				}
+			}
+			if actual := h.is(test.err); actual != expected {
+				t.Errorf("%s(%v): expected %v, got %v", h.name, test.err, expected, actual)
+			}
+		}
+	}
+}
+
+func TestRestApiErrorDecoding(t *testing.T) {
+	err := newApiError(418, http.Header{"Retry-After": []string{"120"}},
+		`{"code":-1003,"msg":"Way too many requests; IP banned."}`)
+	if err.StatusCode != 418 || err.Code != binanceapi.ErrorCodeTooManyRequests ||
+		err.Message != "Way too many requests; IP banned." || err.RetryAfter != 120*time.Second {
+		t.Errorf("unexpected error %+v", err)
+	}
+	if err.Error() != "binance api error -1003: Way too many requests; IP banned." {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	// A body that is not JSON, such as an HTML page from a proxy, leaves
+	// only the status and the raw body.
+	html := "<html><body><h1>502 Bad Gateway</h1></body></html>"
+	err = newApiError(http.StatusBadGateway, nil, html)
+	if err.StatusCode != http.StatusBadGateway || err.Code != 0 || err.Message != "" ||
+		string(err.Body) != html || err.RetryAfter != 0 {
+		t.Errorf("unexpected error %+v", err)
+	}
+	if !strings.Contains(err.Error(), "502 Bad Gateway") || !strings.Contains(err.Error(), html) {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	err = newApiError(http.StatusServiceUnavailable, nil, "")
+	if err.Error() != "binance api error: 503 Service Unavailable" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
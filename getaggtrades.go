@@ -0,0 +1,213 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// The longest time range a request for aggregate trades can span.
+const MaxAggTradesWindow = time.Hour
+
+// The most aggregate trades returned by one request.
+const MaxAggTradesLimit = 1000
+
+// GetAggTrades returns aggregate trades of symbol starting with the trade
+// fromId, or the most recent trades if fromId is -1. The trades are in the
+// format of the aggregate trade stream without the event fields.
+func (c *RestClient) GetAggTrades(symbol string, limit int64, fromId int64) ([]StreamAggTrade, error) {
+	return c.GetAggTradesCtx(context.Background(), symbol, limit, fromId)
+}
+
+func (c *RestClient) GetAggTradesCtx(ctx context.Context, symbol string, limit int64, fromId int64) ([]StreamAggTrade, error) {
+	params := map[string]interface{}{
+		"symbol": symbol,
+	}
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	if fromId > -1 {
+		params["fromId"] = fromId
+	}
+	return c.getAggTrades(ctx, symbol, params)
+}
+
+// GetAggTradesByTime returns aggregate trades of symbol between startTime and
+// endTime, which may be at most MaxAggTradesWindow apart.
+func (c *RestClient) GetAggTradesByTime(symbol string, startTime time.Time, endTime time.Time, limit int64) ([]StreamAggTrade, error) {
+	return c.GetAggTradesByTimeCtx(context.Background(), symbol, startTime, endTime, limit)
+}
+
+func (c *RestClient) GetAggTradesByTimeCtx(ctx context.Context, symbol string, startTime time.Time, endTime time.Time, limit int64) ([]StreamAggTrade, error) {
+	if err := validateTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{
+		"symbol":    symbol,
+		"startTime": startTime.UnixNano() / int64(time.Millisecond),
+		"endTime":   endTime.UnixNano() / int64(time.Millisecond),
+	}
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	return c.getAggTrades(ctx, symbol, params)
+}
+
+// validateTimeRange returns an error unless both times are set and endTime
+// is not before startTime.
+func validateTimeRange(startTime time.Time, endTime time.Time) error {
+	if startTime.IsZero() || endTime.IsZero() {
+		return fmt.Errorf("start and end time must be set")
+	}
+	if endTime.Before(startTime) {
+		return fmt.Errorf("end time %v is before start time %v", endTime, startTime)
+	}
+	return nil
+}
+
+func (c *RestClient) getAggTrades(ctx context.Context, symbol string, params map[string]interface{}) ([]StreamAggTrade, error) {
+	var response []StreamAggTrade
+	if err := c.GetAndDecodeCtx(ctx, "/api/v3/aggTrades", params, &response); err != nil {
+		return nil, err
+	}
+	for i := range response {
+		response[i].Symbol = symbol
+	}
+	return response, nil
+}
+
+// AggTradesIterator walks the aggregate trades of a symbol forward, fetching
+// a page of trades at a time as needed. Without ToId or ToTime it stops at
+// the most recent trade.
+type AggTradesIterator struct {
+	// Stop after the trade with this id, 0 to not stop at an id.
+	ToId int64
+
+	// Stop before the first trade after this time, zero to not stop at a
+	// time.
+	ToTime time.Time
+
+	// The number of trades requested at a time. Defaults to
+	// MaxAggTradesLimit.
+	Limit int64
+
+	client *RestClient
+	symbol string
+
+	// Where to start searching for the first trade while the id of the
+	// next trade, pager.nextId, is -1.
+	fromTime time.Time
+
+	pager tradePager
+	trade StreamAggTrade
+}
+
+// NewAggTradesIterator returns an iterator over the aggregate trades of
+// symbol starting with the trade fromId.
+func (c *RestClient) NewAggTradesIterator(symbol string, fromId int64) *AggTradesIterator {
+	return &AggTradesIterator{
+		Limit:  MaxAggTradesLimit,
+		client: c,
+		symbol: symbol,
+		pager:  tradePager{nextId: fromId},
+	}
+}
+
+// NewAggTradesTimeIterator returns an iterator over the aggregate trades of
+// symbol between startTime and endTime, which may be any distance apart.
+// If either time is zero or endTime is before startTime the iterator stops
+// immediately and Err returns why.
+func (c *RestClient) NewAggTradesTimeIterator(symbol string, startTime time.Time, endTime time.Time) *AggTradesIterator {
+	it := &AggTradesIterator{
+		ToTime:   endTime,
+		Limit:    MaxAggTradesLimit,
+		client:   c,
+		symbol:   symbol,
+		fromTime: startTime,
+		pager:    tradePager{nextId: -1},
+	}
+	if err := validateTimeRange(startTime, endTime); err != nil {
+		it.pager.err = err
+		it.pager.done = true
+	}
+	return it
+}
+
+// Next advances to the next trade, returning false when there are no more
+// trades or an error occurred.
+func (it *AggTradesIterator) Next() bool {
+	return it.NextCtx(context.Background())
+}
+
+func (it *AggTradesIterator) NextCtx(ctx context.Context) bool {
+	if !it.pager.next(ctx, it.ToId, it.ToTime, it.fetch) {
+		return false
+	}
+	it.trade = it.pager.page.(aggTradesPage)[it.pager.pos]
+	return true
+}
+
+// fetch returns the next page of trades. The first trade of a time range is
+// found by searching a window at a time, after which trades are fetched by
+// id as that does not skip over windows without trades.
+func (it *AggTradesIterator) fetch(ctx context.Context) (tradePage, bool, error) {
+	if it.pager.nextId > -1 {
+		page, err := it.client.GetAggTradesCtx(ctx, it.symbol, it.Limit, it.pager.nextId)
+		return aggTradesPage(page), int64(len(page)) < it.Limit, err
+	}
+
+	for {
+		endTime := it.fromTime.Add(MaxAggTradesWindow - time.Millisecond)
+		if !it.ToTime.IsZero() && endTime.After(it.ToTime) {
+			endTime = it.ToTime
+		}
+		page, err := it.client.GetAggTradesByTimeCtx(ctx, it.symbol, it.fromTime, endTime, it.Limit)
+		if err != nil || len(page) > 0 {
+			return aggTradesPage(page), false, err
+		}
+		if (!it.ToTime.IsZero() && !endTime.Before(it.ToTime)) ||
+			endTime.After(it.client.serverNow()) {
+			return nil, true, nil
+		}
+		it.fromTime = endTime.Add(time.Millisecond)
+	}
+}
+
+// Trade returns the current trade.
+func (it *AggTradesIterator) Trade() StreamAggTrade {
+	return it.trade
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *AggTradesIterator) Err() error {
+	return it.pager.err
+}
+
+type aggTradesPage []StreamAggTrade
+
+func (p aggTradesPage) len() int               { return len(p) }
+func (p aggTradesPage) id(i int) int64         { return p[i].TradeID }
+func (p aggTradesPage) timeMillis(i int) int64 { return p[i].TradeTimeMillis }
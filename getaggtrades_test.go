@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func TestAggTradesTimeIterator(t *testing.T) {
+	server, trades := newTradesServer(t)
+	defer server.Close()
+
+	// Start before the first trade and end at the 8th, across the windows
+	// without trades.
+	first := time.Unix(0, trades[0].TimeMillis*int64(time.Millisecond))
+	end := time.Unix(0, trades[7].TimeMillis*int64(time.Millisecond))
+	it := server.Client().NewAggTradesTimeIterator("BTCUSDT", first.Add(-90*time.Minute), end)
+	it.Limit = 2
+	count := 0
+	for it.Next() {
+		if id := it.Trade().TradeID; id != trades[count].Id {
+			t.Errorf("trade %d: expected id %d, got %d", count, trades[count].Id, id)
+		}
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != 8 {
+		t.Errorf("expected 8 trades, got %d", count)
+	}
+}
+
+func TestAggTradesTimeIteratorInvalidRange(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	now := time.Now()
+	ranges := [][2]time.Time{
+		{time.Time{}, now},
+		{now.Add(-time.Hour), time.Time{}},
+		{now, now.Add(-time.Hour)},
+	}
+	for _, r := range ranges {
+		it := client.NewAggTradesTimeIterator("BTCUSDT", r[0], r[1])
+		if it.Next() {
+			t.Errorf("%v to %v: expected no trades", r[0], r[1])
+		}
+		if it.Err() == nil {
+			t.Errorf("%v to %v: expected an error", r[0], r[1])
+		}
+		if _, err := client.GetAggTradesByTime("BTCUSDT", r[0], r[1], 10); err == nil {
+			t.Errorf("%v to %v: expected an error from GetAggTradesByTime", r[0], r[1])
+		}
+	}
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("expected no requests, got %d", n)
+	}
+}
@@ -23,15 +23,13 @@
 
 package binanceapi
 
+import "context"
+
 type ExchangeInfoResponse struct {
-	Timezone         string `json:"timezone"`
-	ServerTimeMillis int64  `json:"serverTime"`
-	RateLimits       []struct {
-		RateLimitType     string `json:"rateLimitType"`
-		RateLimitInterval string `json:"rateLimitInterval"`
-		Limit             int64  `json:"limit"`
-	}
-	Symbols []SymbolInfoResponse `json:"symbols"`
+	Timezone         string               `json:"timezone"`
+	ServerTimeMillis int64                `json:"serverTime"`
+	RateLimits       []RateLimit          `json:"rateLimits"`
+	Symbols          []SymbolInfoResponse `json:"symbols"`
 }
 
 type SymbolInfoResponse struct {
@@ -58,8 +56,15 @@ type SymbolFilterResponse struct {
 }
 
 func (c *RestClient) GetExchangeInfo() (ExchangeInfoResponse, error) {
+	return c.GetExchangeInfoCtx(context.Background())
+}
+
+func (c *RestClient) GetExchangeInfoCtx(ctx context.Context) (ExchangeInfoResponse, error) {
 	endpoint := "/api/v1/exchangeInfo"
 	var response ExchangeInfoResponse
-	err := c.GetAndDecode(endpoint, nil, &response)
-	return response, err
+	if err := c.GetAndDecodeCtx(ctx, endpoint, nil, &response); err != nil {
+		return response, err
+	}
+	c.SetRateLimits(response.RateLimits)
+	return response, nil
 }
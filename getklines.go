@@ -0,0 +1,222 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type KlineInterval string
+
+const (
+	KlineInterval1s  KlineInterval = "1s"
+	KlineInterval1m  KlineInterval = "1m"
+	KlineInterval3m  KlineInterval = "3m"
+	KlineInterval5m  KlineInterval = "5m"
+	KlineInterval15m KlineInterval = "15m"
+	KlineInterval30m KlineInterval = "30m"
+	KlineInterval1h  KlineInterval = "1h"
+	KlineInterval2h  KlineInterval = "2h"
+	KlineInterval4h  KlineInterval = "4h"
+	KlineInterval6h  KlineInterval = "6h"
+	KlineInterval8h  KlineInterval = "8h"
+	KlineInterval12h KlineInterval = "12h"
+	KlineInterval1d  KlineInterval = "1d"
+	KlineInterval3d  KlineInterval = "3d"
+	KlineInterval1w  KlineInterval = "1w"
+	KlineInterval1M  KlineInterval = "1M"
+)
+
+// The most klines returned by one request.
+const MaxKlinesLimit = 1000
+
+// GET /api/v3/klines and /api/v3/uiKlines
+type Kline struct {
+	OpenTimeMillis      int64
+	Open                float64
+	High                float64
+	Low                 float64
+	Close               float64
+	Volume              float64
+	CloseTimeMillis     int64
+	QuoteVolume         float64
+	TradeCount          int64
+	TakerBuyVolume      float64
+	TakerBuyQuoteVolume float64
+}
+
+// A kline is sent as an array of its fields, with decimals as strings.
+func (k *Kline) UnmarshalJSON(b []byte) error {
+	var fields []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return err
+	}
+	if len(fields) < 11 {
+		return fmt.Errorf("kline has %d fields, expected at least 11", len(fields))
+	}
+
+	var err error
+	decodeInt := func(i int) int64 {
+		var val int64
+		if err == nil {
+			val, err = decodeNumberInt64(fields[i])
+		}
+		return val
+	}
+	decodeFloat := func(i int) float64 {
+		var val float64
+		if err == nil {
+			val, err = decodeStringFloat64(fields[i])
+		}
+		return val
+	}
+	*k = Kline{
+		OpenTimeMillis:      decodeInt(0),
+		Open:                decodeFloat(1),
+		High:                decodeFloat(2),
+		Low:                 decodeFloat(3),
+		Close:               decodeFloat(4),
+		Volume:              decodeFloat(5),
+		CloseTimeMillis:     decodeInt(6),
+		QuoteVolume:         decodeFloat(7),
+		TradeCount:          decodeInt(8),
+		TakerBuyVolume:      decodeFloat(9),
+		TakerBuyQuoteVolume: decodeFloat(10),
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode kline: %v", err)
+	}
+	return nil
+}
+
+func (k *Kline) OpenTime() time.Time {
+	return time.Unix(0, k.OpenTimeMillis*int64(time.Millisecond))
+}
+
+func (k *Kline) CloseTime() time.Time {
+	return time.Unix(0, k.CloseTimeMillis*int64(time.Millisecond))
+}
+
+type KlinesParameters struct {
+	Symbol   string
+	Interval KlineInterval
+
+	// Optional, the most recent klines are returned without them.
+	StartTime time.Time
+	EndTime   time.Time
+
+	// The time zone intervals are aligned to as an offset from UTC, eg.
+	// "+08:00" or "-1:30". Defaults to UTC. Open and close times are always
+	// in UTC.
+	TimeZone string
+
+	// Defaults to 500, at most MaxKlinesLimit.
+	Limit int64
+}
+
+func (p KlinesParameters) params() map[string]interface{} {
+	params := map[string]interface{}{
+		"symbol":   p.Symbol,
+		"interval": p.Interval,
+	}
+	if !p.StartTime.IsZero() {
+		params["startTime"] = p.StartTime.UnixNano() / int64(time.Millisecond)
+	}
+	if !p.EndTime.IsZero() {
+		params["endTime"] = p.EndTime.UnixNano() / int64(time.Millisecond)
+	}
+	if p.TimeZone != "" {
+		params["timeZone"] = p.TimeZone
+	}
+	if p.Limit > 0 {
+		params["limit"] = p.Limit
+	}
+	return params
+}
+
+func (c *RestClient) GetKlines(params KlinesParameters) ([]Kline, error) {
+	return c.GetKlinesCtx(context.Background(), params)
+}
+
+func (c *RestClient) GetKlinesCtx(ctx context.Context, params KlinesParameters) ([]Kline, error) {
+	var response []Kline
+	err := c.GetAndDecodeCtx(ctx, "/api/v3/klines", params.params(), &response)
+	return response, err
+}
+
+// GetUIKlines returns klines adjusted for presentation in a chart, in the
+// same format as GetKlines.
+func (c *RestClient) GetUIKlines(params KlinesParameters) ([]Kline, error) {
+	return c.GetUIKlinesCtx(context.Background(), params)
+}
+
+func (c *RestClient) GetUIKlinesCtx(ctx context.Context, params KlinesParameters) ([]Kline, error) {
+	var response []Kline
+	err := c.GetAndDecodeCtx(ctx, "/api/v3/uiKlines", params.params(), &response)
+	return response, err
+}
+
+// GetKlinesRange returns all klines from params.StartTime, which must be
+// set, to params.EndTime, or up to the most recent kline if EndTime is zero,
+// making as many requests as needed. Limit sets the number of klines
+// requested at a time and defaults to MaxKlinesLimit.
+func (c *RestClient) GetKlinesRange(params KlinesParameters) ([]Kline, error) {
+	return c.GetKlinesRangeCtx(context.Background(), params)
+}
+
+func (c *RestClient) GetKlinesRangeCtx(ctx context.Context, params KlinesParameters) ([]Kline, error) {
+	if params.StartTime.IsZero() {
+		return nil, fmt.Errorf("a range of klines requires a start time")
+	}
+	if params.Limit <= 0 {
+		params.Limit = MaxKlinesLimit
+	}
+	klines := []Kline{}
+	for {
+		page, err := c.GetKlinesCtx(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, page...)
+		if int64(len(page)) < params.Limit {
+			return klines, nil
+		}
+		last := page[len(page)-1]
+		startTime := last.CloseTime().Add(time.Millisecond)
+		if !startTime.After(params.StartTime) {
+			return nil, fmt.Errorf("klines from %v end at %v, before the start time",
+				params.StartTime, last.CloseTime())
+		}
+		params.StartTime = startTime
+		if !params.EndTime.IsZero() && params.StartTime.After(params.EndTime) {
+			return klines, nil
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func TestDecodeKline(t *testing.T) {
+	// The example from the Binance API documentation.
+	payload := `[1499040000000,"0.01634790","0.80000000","0.01575800","0.01577100",` +
+		`"148976.11427815",1499644799999,"2434.19055334",308,"1756.87402397",` +
+		`"28.46694368","0"]`
+	expected := binanceapi.Kline{
+		OpenTimeMillis:      1499040000000,
+		Open:                0.0163479,
+		High:                0.8,
+		Low:                 0.015758,
+		Close:               0.015771,
+		Volume:              148976.11427815,
+		CloseTimeMillis:     1499644799999,
+		QuoteVolume:         2434.19055334,
+		TradeCount:          308,
+		TakerBuyVolume:      1756.87402397,
+		TakerBuyQuoteVolume: 28.46694368,
+	}
+	var kline binanceapi.Kline
+	if err := json.Unmarshal([]byte(payload), &kline); err != nil {
+		t.Fatal(err)
+	}
+	if kline != expected {
+		t.Errorf("expected %+v, got %+v", expected, kline)
+	}
+
+	if err := json.Unmarshal([]byte(`[1499040000000,"0.1"]`), &kline); err == nil {
+		t.Error("expected an error for a short kline")
+	}
+}
+
+func TestGetKlinesRange(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	// 41 hourly klines fetched 7 at a time, the last page cut off by the
+	// end time.
+	start := time.Now().Add(-50 * time.Hour).Truncate(time.Hour)
+	klines, err := client.GetKlinesRange(binanceapi.KlinesParameters{
+		Symbol:    "BTCUSDT",
+		Interval:  binanceapi.KlineInterval1h,
+		StartTime: start,
+		EndTime:   start.Add(40*time.Hour + 30*time.Minute),
+		Limit:     7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(klines) != 41 {
+		t.Fatalf("expected 41 klines, got %d", len(klines))
+	}
+	for i, kline := range klines {
+		openTime := start.Add(time.Duration(i) * time.Hour)
+		if !kline.OpenTime().Equal(openTime) {
+			t.Errorf("kline %d: expected open time %v, got %v", i, openTime, kline.OpenTime())
+		}
+		if !kline.CloseTime().Equal(openTime.Add(time.Hour - time.Millisecond)) {
+			t.Errorf("kline %d: unexpected close time %v", i, kline.CloseTime())
+		}
+	}
+	if n := countRequests(server, "/api/v3/klines"); n != 6 {
+		t.Errorf("expected 6 requests, got %d", n)
+	}
+
+	if _, err := client.GetKlinesRange(binanceapi.KlinesParameters{
+		Symbol:   "BTCUSDT",
+		Interval: binanceapi.KlineInterval1h,
+	}); err == nil {
+		t.Error("expected an error without a start time")
+	}
+}
+
+func TestGetKlinesRangeNotAdvancing(t *testing.T) {
+	// A server that ignores startTime and always returns the same klines.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[` +
+			`[1499040000000,"1","1","1","1","1",1499043599999,"1",1,"1","1","0"],` +
+			`[1499043600000,"1","1","1","1","1",1499047199999,"1",1,"1","1","0"]]`))
+	}))
+	defer server.Close()
+
+	client := binanceapi.NewRestClient(binanceapi.WithBaseUrl(server.URL))
+	_, err := client.GetKlinesRange(binanceapi.KlinesParameters{
+		Symbol:    "BTCUSDT",
+		Interval:  binanceapi.KlineInterval1h,
+		StartTime: time.Unix(0, 1499040000000*int64(time.Millisecond)),
+		Limit:     2,
+	})
+	if err == nil {
+		t.Error("expected an error for klines that do not advance")
+	}
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// The maximum limit of GetOrderBook. 0 uses the server default of 100.
+const MaxOrderBookLimit = 5000
+
+// GET /api/v3/depth
+type OrderBookResponse struct {
+	LastUpdateID int64
+	Bids         []BidEntry
+	Asks         []AskEntry
+}
+
+// The response has the same format as the partial book depth stream.
+func (r *OrderBookResponse) UnmarshalJSON(b []byte) error {
+	message, err := DecodePartialBookDepthStream(b)
+	if err != nil {
+		return err
+	}
+	*r = OrderBookResponse(message)
+	return nil
+}
+
+// ValidateOrderBookLimit returns an error if limit is not 0 or between 1
+// and MaxOrderBookLimit.
+func ValidateOrderBookLimit(limit int64) error {
+	if limit < 0 || limit > MaxOrderBookLimit {
+		return fmt.Errorf("invalid order book limit %d, must be between 1 and %d",
+			limit, MaxOrderBookLimit)
+	}
+	return nil
+}
+
+func (c *RestClient) GetOrderBook(symbol string, limit int64) (OrderBookResponse, error) {
+	return c.GetOrderBookCtx(context.Background(), symbol, limit)
+}
+
+func (c *RestClient) GetOrderBookCtx(ctx context.Context, symbol string, limit int64) (OrderBookResponse, error) {
+	var response OrderBookResponse
+	if err := ValidateOrderBookLimit(limit); err != nil {
+		return response, err
+	}
+	params := map[string]interface{}{
+		"symbol": symbol,
+	}
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	err := c.GetAndDecodeCtx(ctx, "/api/v3/depth", params, &response)
+	return response, err
+}
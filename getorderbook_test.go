@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func TestDecodeOrderBook(t *testing.T) {
+	payload := `{"lastUpdateId":1027024,` +
+		`"bids":[["4.00000000","431.00000000"],["3.99000000","12.50000000"]],` +
+		`"asks":[["4.00000200","12.00000000"]]}`
+	expected := binanceapi.OrderBookResponse{
+		LastUpdateID: 1027024,
+		Bids:         []binanceapi.BidEntry{{Price: 4, Volume: 431}, {Price: 3.99, Volume: 12.5}},
+		Asks:         []binanceapi.AskEntry{{Price: 4.000002, Volume: 12}},
+	}
+	var book binanceapi.OrderBookResponse
+	if err := json.Unmarshal([]byte(payload), &book); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(book, expected) {
+		t.Errorf("expected %+v, got %+v", expected, book)
+	}
+}
+
+func TestValidateOrderBookLimit(t *testing.T) {
+	for _, limit := range []int64{0, 1, 7, 100, 250, binanceapi.MaxOrderBookLimit} {
+		if err := binanceapi.ValidateOrderBookLimit(limit); err != nil {
+			t.Errorf("limit %d: %v", limit, err)
+		}
+	}
+	for _, limit := range []int64{-1, binanceapi.MaxOrderBookLimit + 1} {
+		if err := binanceapi.ValidateOrderBookLimit(limit); err == nil {
+			t.Errorf("limit %d: expected an error", limit)
+		}
+	}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	book, err := client.GetOrderBook("BTCUSDT", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(book.Bids) != 7 || len(book.Asks) != 7 {
+		t.Fatalf("expected 7 levels, got %d bids and %d asks", len(book.Bids), len(book.Asks))
+	}
+	if book.Bids[0].Price >= book.Asks[0].Price || book.Bids[1].Price >= book.Bids[0].Price {
+		t.Errorf("unexpected book %+v", book)
+	}
+
+	if _, err := client.GetOrderBook("BTCUSDT", binanceapi.MaxOrderBookLimit+1); err == nil {
+		t.Error("expected an error for a limit over the maximum")
+	}
+	if n := countRequests(server, "/api/v3/depth"); n != 1 {
+		t.Errorf("expected 1 depth request, got %d", n)
+	}
+}
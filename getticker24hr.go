@@ -0,0 +1,164 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// TickerStatistics are the rolling 24 hour statistics of a symbol, as
+// returned by GET /api/v3/ticker/24hr and sent on the ticker stream.
+type TickerStatistics struct {
+	Symbol               string  `json:"symbol"`
+	PriceChange          float64 `json:"priceChange,string"`
+	PriceChangePercent   float64 `json:"priceChangePercent,string"`
+	WeightedAveragePrice float64 `json:"weightedAvgPrice,string"`
+	PreviousDayClose     float64 `json:"prevClosePrice,string"`
+	CurrentDayClose      float64 `json:"lastPrice,string"`
+	CloseTradeQuantity   float64 `json:"lastQty,string"`
+	Bid                  float64 `json:"bidPrice,string"`
+	BidQuantity          float64 `json:"bidQty,string"`
+	Ask                  float64 `json:"askPrice,string"`
+	AskQuantity          float64 `json:"askQty,string"`
+	OpenPrice            float64 `json:"openPrice,string"`
+	HighPrice            float64 `json:"highPrice,string"`
+	LowPrice             float64 `json:"lowPrice,string"`
+	TotalBaseVolume      float64 `json:"volume,string"`
+	TotalQuoteVolume     float64 `json:"quoteVolume,string"`
+	StatsOpenTime        int64   `json:"openTime"`
+	StatsCloseTime       int64   `json:"closeTime"`
+	FirstTradeID         int64   `json:"firstId"`
+	LastTradeID          int64   `json:"lastId"`
+	TotalNumberTrades    int64   `json:"count"`
+}
+
+// MiniTickerStatistics are the statistics of the MINI ticker type, without
+// the price change, last quantity and best bid and ask.
+type MiniTickerStatistics struct {
+	Symbol            string  `json:"symbol"`
+	OpenPrice         float64 `json:"openPrice,string"`
+	HighPrice         float64 `json:"highPrice,string"`
+	LowPrice          float64 `json:"lowPrice,string"`
+	CurrentDayClose   float64 `json:"lastPrice,string"`
+	TotalBaseVolume   float64 `json:"volume,string"`
+	TotalQuoteVolume  float64 `json:"quoteVolume,string"`
+	StatsOpenTime     int64   `json:"openTime"`
+	StatsCloseTime    int64   `json:"closeTime"`
+	FirstTradeID      int64   `json:"firstId"`
+	LastTradeID       int64   `json:"lastId"`
+	TotalNumberTrades int64   `json:"count"`
+}
+
+func (c *RestClient) getTicker24hr(ctx context.Context, params map[string]interface{}, mini bool, response interface{}) error {
+	if mini {
+		params["type"] = "MINI"
+	}
+	return c.GetAndDecodeCtx(ctx, "/api/v3/ticker/24hr", params, response)
+}
+
+// symbolsParams rejects an empty list, which the server refuses rather than
+// taking to mean all symbols.
+func symbolsParams(symbols []string) (map[string]interface{}, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols given")
+	}
+	return map[string]interface{}{"symbols": symbols}, nil
+}
+
+// GetTicker24hr returns the 24 hour statistics of symbol.
+func (c *RestClient) GetTicker24hr(symbol string) (TickerStatistics, error) {
+	return c.GetTicker24hrCtx(context.Background(), symbol)
+}
+
+func (c *RestClient) GetTicker24hrCtx(ctx context.Context, symbol string) (TickerStatistics, error) {
+	var response TickerStatistics
+	err := c.getTicker24hr(ctx, map[string]interface{}{"symbol": symbol}, false, &response)
+	return response, err
+}
+
+// GetTicker24hrSymbols returns the 24 hour statistics of each of symbols,
+// which must not be empty.
+func (c *RestClient) GetTicker24hrSymbols(symbols []string) ([]TickerStatistics, error) {
+	return c.GetTicker24hrSymbolsCtx(context.Background(), symbols)
+}
+
+func (c *RestClient) GetTicker24hrSymbolsCtx(ctx context.Context, symbols []string) ([]TickerStatistics, error) {
+	params, err := symbolsParams(symbols)
+	if err != nil {
+		return nil, err
+	}
+	var response []TickerStatistics
+	err = c.getTicker24hr(ctx, params, false, &response)
+	return response, err
+}
+
+// GetTicker24hrAll returns the 24 hour statistics of all symbols.
+func (c *RestClient) GetTicker24hrAll() ([]TickerStatistics, error) {
+	return c.GetTicker24hrAllCtx(context.Background())
+}
+
+func (c *RestClient) GetTicker24hrAllCtx(ctx context.Context) ([]TickerStatistics, error) {
+	var response []TickerStatistics
+	err := c.getTicker24hr(ctx, map[string]interface{}{}, false, &response)
+	return response, err
+}
+
+// GetMiniTicker24hr returns the MINI 24 hour statistics of symbol.
+func (c *RestClient) GetMiniTicker24hr(symbol string) (MiniTickerStatistics, error) {
+	return c.GetMiniTicker24hrCtx(context.Background(), symbol)
+}
+
+func (c *RestClient) GetMiniTicker24hrCtx(ctx context.Context, symbol string) (MiniTickerStatistics, error) {
+	var response MiniTickerStatistics
+	err := c.getTicker24hr(ctx, map[string]interface{}{"symbol": symbol}, true, &response)
+	return response, err
+}
+
+// GetMiniTicker24hrSymbols returns the MINI 24 hour statistics of each of
+// symbols, which must not be empty.
+func (c *RestClient) GetMiniTicker24hrSymbols(symbols []string) ([]MiniTickerStatistics, error) {
+	return c.GetMiniTicker24hrSymbolsCtx(context.Background(), symbols)
+}
+
+func (c *RestClient) GetMiniTicker24hrSymbolsCtx(ctx context.Context, symbols []string) ([]MiniTickerStatistics, error) {
+	params, err := symbolsParams(symbols)
+	if err != nil {
+		return nil, err
+	}
+	var response []MiniTickerStatistics
+	err = c.getTicker24hr(ctx, params, true, &response)
+	return response, err
+}
+
+// GetMiniTicker24hrAll returns the MINI 24 hour statistics of all symbols.
+func (c *RestClient) GetMiniTicker24hrAll() ([]MiniTickerStatistics, error) {
+	return c.GetMiniTicker24hrAllCtx(context.Background())
+}
+
+func (c *RestClient) GetMiniTicker24hrAllCtx(ctx context.Context) ([]MiniTickerStatistics, error) {
+	var response []MiniTickerStatistics
+	err := c.getTicker24hr(ctx, map[string]interface{}{}, true, &response)
+	return response, err
+}
@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func TestGetTicker24hr(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	now := time.Now()
+	server.AddTrade("BTCUSDT", 49000, 1, false, now.Add(-2*time.Hour))
+	server.AddTrade("BTCUSDT", 51000, 2, true, now.Add(-time.Hour))
+
+	full, err := client.GetTicker24hr("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.OpenPrice != 49000 || full.CurrentDayClose != 51000 || full.TotalBaseVolume != 3 ||
+		full.TotalNumberTrades != 2 {
+		t.Errorf("unexpected statistics: %+v", full)
+	}
+	if full.PriceChange != 2000 || full.CloseTradeQuantity != 2 || full.Bid == 0 || full.Ask == 0 {
+		t.Errorf("full statistics missing fields: %+v", full)
+	}
+
+	mini, err := client.GetMiniTicker24hr("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mini.Symbol != "BTCUSDT" || mini.OpenPrice != 49000 || mini.HighPrice != 51000 ||
+		mini.LowPrice != 49000 || mini.CurrentDayClose != 51000 || mini.TotalBaseVolume != 3 ||
+		mini.TotalQuoteVolume != 151000 || mini.TotalNumberTrades != 2 {
+		t.Errorf("unexpected mini statistics: %+v", mini)
+	}
+	if request := server.Requests()[len(server.Requests())-1]; request.Params.Get("type") != "MINI" {
+		t.Errorf("expected type MINI, got %q", request.Params.Get("type"))
+	}
+}
+
+func TestGetTicker24hrSymbols(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	stats, err := client.GetTicker24hrSymbols([]string{"ETHUSDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].Symbol != "ETHUSDT" {
+		t.Errorf("unexpected statistics: %+v", stats)
+	}
+
+	all, err := client.GetMiniTicker24hrAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected 2 symbols, got %+v", all)
+	}
+
+	// An empty list fails without a request rather than being sent as [].
+	requests := len(server.Requests())
+	if _, err := client.GetTicker24hrSymbols(nil); err == nil {
+		t.Error("expected an error for no symbols")
+	}
+	if _, err := client.GetMiniTicker24hrSymbols([]string{}); err == nil {
+		t.Error("expected an error for no symbols")
+	}
+	if n := len(server.Requests()); n != requests {
+		t.Errorf("expected no requests, got %d", n-requests)
+	}
+}
@@ -0,0 +1,226 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"time"
+)
+
+// The most trades returned by one request for recent or historical trades.
+const MaxTradesLimit = 1000
+
+// GET /api/v3/trades and /api/v3/historicalTrades
+type TradesResponseEntry struct {
+	Id            int64   `json:"id"`
+	Price         float64 `json:"price,string"`
+	Quantity      float64 `json:"qty,string"`
+	QuoteQuantity float64 `json:"quoteQty,string"`
+	TimeMillis    int64   `json:"time"`
+	IsBuyerMaker  bool    `json:"isBuyerMaker"`
+	IsBestMatch   bool    `json:"isBestMatch"`
+}
+
+// GetTrades returns the most recent trades of symbol, oldest first. A limit
+// of 0 uses the server default of 500.
+func (c *RestClient) GetTrades(symbol string, limit int64) ([]TradesResponseEntry, error) {
+	return c.GetTradesCtx(context.Background(), symbol, limit)
+}
+
+func (c *RestClient) GetTradesCtx(ctx context.Context, symbol string, limit int64) ([]TradesResponseEntry, error) {
+	params := map[string]interface{}{
+		"symbol": symbol,
+	}
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	var response []TradesResponseEntry
+	err := c.GetAndDecodeCtx(ctx, "/api/v3/trades", params, &response)
+	return response, err
+}
+
+// GetHistoricalTrades returns trades of symbol starting with the trade
+// fromId, or the most recent trades if fromId is -1. This requires the API
+// key but not a signature.
+func (c *RestClient) GetHistoricalTrades(symbol string, limit int64, fromId int64) ([]TradesResponseEntry, error) {
+	return c.GetHistoricalTradesCtx(context.Background(), symbol, limit, fromId)
+}
+
+func (c *RestClient) GetHistoricalTradesCtx(ctx context.Context, symbol string, limit int64, fromId int64) ([]TradesResponseEntry, error) {
+	params := map[string]interface{}{
+		"symbol": symbol,
+	}
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	if fromId > -1 {
+		params["fromId"] = fromId
+	}
+	var response []TradesResponseEntry
+	err := c.RequestAndDecode(ctx, "GET", "/api/v3/historicalTrades",
+		SecurityTypeMarketData, params, &response)
+	return response, err
+}
+
+// HistoricalTradesIterator walks the historical trades of a symbol forward
+// from a trade id, fetching a page of trades at a time as needed.
+//
+//	trades := client.NewHistoricalTradesIterator("BTCUSDT", fromId)
+//	trades.ToTime = time.Now().Add(-time.Hour)
+//	for trades.Next() {
+//	    trade := trades.Trade()
+//	}
+//	if err := trades.Err(); err != nil {
+//	}
+//
+// Without ToId or ToTime the iterator stops at the most recent trade.
+type HistoricalTradesIterator struct {
+	// Stop after the trade with this id, 0 to not stop at an id.
+	ToId int64
+
+	// Stop before the first trade after this time, zero to not stop at a
+	// time.
+	ToTime time.Time
+
+	// The number of trades requested at a time. Defaults to MaxTradesLimit.
+	Limit int64
+
+	client *RestClient
+	symbol string
+	pager  tradePager
+	trade  TradesResponseEntry
+}
+
+func (c *RestClient) NewHistoricalTradesIterator(symbol string, fromId int64) *HistoricalTradesIterator {
+	return &HistoricalTradesIterator{
+		Limit:  MaxTradesLimit,
+		client: c,
+		symbol: symbol,
+		pager:  tradePager{nextId: fromId},
+	}
+}
+
+// Next advances to the next trade, returning false when there are no more
+// trades or an error occurred.
+func (it *HistoricalTradesIterator) Next() bool {
+	return it.NextCtx(context.Background())
+}
+
+func (it *HistoricalTradesIterator) NextCtx(ctx context.Context) bool {
+	if !it.pager.next(ctx, it.ToId, it.ToTime, it.fetch) {
+		return false
+	}
+	it.trade = it.pager.page.(tradesPage)[it.pager.pos]
+	return true
+}
+
+func (it *HistoricalTradesIterator) fetch(ctx context.Context) (tradePage, bool, error) {
+	page, err := it.client.GetHistoricalTradesCtx(ctx, it.symbol, it.Limit, it.pager.nextId)
+	return tradesPage(page), int64(len(page)) < it.Limit, err
+}
+
+// Trade returns the current trade.
+func (it *HistoricalTradesIterator) Trade() TradesResponseEntry {
+	return it.trade
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *HistoricalTradesIterator) Err() error {
+	return it.pager.err
+}
+
+type tradesPage []TradesResponseEntry
+
+func (p tradesPage) len() int               { return len(p) }
+func (p tradesPage) id(i int) int64         { return p[i].Id }
+func (p tradesPage) timeMillis(i int) int64 { return p[i].TimeMillis }
+
+// tradePage is a page of trades, oldest first, as walked by tradePager.
+type tradePage interface {
+	len() int
+	id(i int) int64
+	timeMillis(i int) int64
+}
+
+// tradePager walks forward through trades a page at a time for the trade
+// iterators, stopping after the trade toId or before the first trade after
+// toTime.
+type tradePager struct {
+	// The id of the first trade of the next page.
+	nextId int64
+
+	// The current page and the index of the current trade in it.
+	page tradePage
+	pos  int
+
+	done bool
+	err  error
+}
+
+// next advances to the next trade, calling fetch when the current page is
+// used up. fetch returns the next page and whether it is the last, which is
+// usually the case for a page shorter than the limit as the most recent
+// trade has been reached.
+func (p *tradePager) next(ctx context.Context, toId int64, toTime time.Time,
+	fetch func(ctx context.Context) (tradePage, bool, error)) bool {
+	if p.page != nil && p.pos+1 < p.page.len() {
+		p.pos++
+	} else {
+		p.page = nil
+		if p.done {
+			return false
+		}
+		page, last, err := fetch(ctx)
+		if err != nil {
+			p.err = err
+			p.done = true
+			return false
+		}
+		if page == nil || page.len() == 0 {
+			p.done = true
+			return false
+		}
+		end := page.len() - 1
+		p.nextId = page.id(end) + 1
+		p.page, p.pos = page, 0
+		if last || (toId > 0 && p.nextId > toId) || pastEnd(page, end, toId, toTime) {
+			p.done = true
+		}
+	}
+
+	if pastEnd(p.page, p.pos, toId, toTime) {
+		p.page = nil
+		p.done = true
+		return false
+	}
+	return true
+}
+
+func pastEnd(page tradePage, i int, toId int64, toTime time.Time) bool {
+	if toId > 0 && page.id(i) > toId {
+		return true
+	}
+	return !toTime.IsZero() &&
+		page.timeMillis(i) > toTime.UnixNano()/int64(time.Millisecond)
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+// newTradesServer returns a fake server with trades spread over several
+// hours with a gap, and the trades as returned by GetTrades.
+func newTradesServer(t *testing.T) (*binanceapitest.Server, []binanceapi.TradesResponseEntry) {
+	server := binanceapitest.NewServer()
+	start := time.Now().Add(-12 * time.Hour).Truncate(time.Millisecond)
+	for i := 0; i < 10; i++ {
+		at := start.Add(time.Duration(i) * 20 * time.Minute)
+		if i >= 5 {
+			// Leave hours without trades.
+			at = at.Add(3 * time.Hour)
+		}
+		server.AddTrade("BTCUSDT", 50000, 0.1, i%2 == 0, at)
+	}
+	trades, err := server.Client().GetTrades("BTCUSDT", binanceapi.MaxTradesLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 10 {
+		t.Fatalf("expected 10 trades, got %d", len(trades))
+	}
+	return server, trades
+}
+
+func TestHistoricalTradesIterator(t *testing.T) {
+	server, trades := newTradesServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		toId   int64
+		toTime time.Time
+		count  int
+	}{
+		{0, time.Time{}, 10},
+		{trades[4].Id, time.Time{}, 5},
+		{0, time.Unix(0, trades[6].TimeMillis*int64(time.Millisecond)), 7},
+	}
+	for _, test := range tests {
+		it := server.Client().NewHistoricalTradesIterator("BTCUSDT", trades[0].Id)
+		it.Limit = 3
+		it.ToId = test.toId
+		it.ToTime = test.toTime
+		count := 0
+		for it.Next() {
+			if id := it.Trade().Id; id != trades[count].Id {
+				t.Errorf("trade %d: expected id %d, got %d", count, trades[count].Id, id)
+			}
+			count++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if count != test.count {
+			t.Errorf("ToId %d, ToTime %v: expected %d trades, got %d", test.toId, test.toTime, test.count, count)
+		}
+	}
+}
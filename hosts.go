@@ -0,0 +1,295 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The equivalent REST API hosts of production, for use with WithBaseUrls.
+var ProductionRestUrls = []string{
+	"https://api.binance.com",
+	"https://api1.binance.com",
+	"https://api2.binance.com",
+	"https://api3.binance.com",
+}
+
+const (
+	// How often the latency of each host is measured by default.
+	DefaultHostProbeInterval = time.Minute
+
+	// How long a failed host is avoided unless a probe finds it healthy.
+	hostFailureCooldown = 30 * time.Second
+)
+
+// HostStatus is the state of a REST API host as seen by the client.
+type HostStatus struct {
+	Url string
+
+	// Round trip time of the last probe, 0 if not yet probed.
+	Latency time.Duration
+
+	Healthy bool
+}
+
+type host struct {
+	url      string
+	latency  time.Duration
+	failedAt time.Time
+}
+
+func (h *host) healthy(now time.Time) bool {
+	return h.failedAt.IsZero() || now.Sub(h.failedAt) > hostFailureCooldown
+}
+
+// hostSelector picks the host to send a request to from a list of
+// equivalent hosts, preferring the fastest healthy one.
+type hostSelector struct {
+	mu    sync.Mutex
+	hosts []*host
+
+	lastProbe time.Time
+	probing   bool
+}
+
+func newHostSelector(urls []string) *hostSelector {
+	s := &hostSelector{}
+	for _, url := range urls {
+		s.hosts = append(s.hosts, &host{
+			url: strings.TrimSuffix(url, "/"),
+		})
+	}
+	return s
+}
+
+// WithBaseUrls sets several equivalent root URLs, such as
+// ProductionRestUrls. The latency of each is measured periodically and
+// requests are sent to the fastest healthy host, failing over to the others
+// on connection errors and 5xx responses.
+func WithBaseUrls(urls ...string) RestClientOption {
+	return func(c *RestClient) {
+		c.hosts = newHostSelector(urls)
+	}
+}
+
+// WithHostProbeInterval sets how often the latency of the hosts given with
+// WithBaseUrls is measured. 0 disables periodic probing.
+func WithHostProbeInterval(interval time.Duration) RestClientOption {
+	return func(c *RestClient) {
+		c.hostProbeInterval = interval
+	}
+}
+
+func (s *hostSelector) len() int {
+	return len(s.hosts)
+}
+
+// urls returns the root URLs of the hosts in the order they were given.
+func (s *hostSelector) urls() []string {
+	urls := make([]string, len(s.hosts))
+	for i, h := range s.hosts {
+		urls[i] = h.url
+	}
+	return urls
+}
+
+// next returns the best host not in tried, or the best host overall if all
+// have been tried.
+func (s *hostSelector) next(tried map[string]bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var best *host
+	for _, h := range s.hosts {
+		if tried[h.url] {
+			continue
+		}
+		if best == nil || s.better(h, best, now) {
+			best = h
+		}
+	}
+	if best == nil {
+		best = s.hosts[0]
+	}
+	return best.url
+}
+
+// better returns true if a is preferred over b.
+func (s *hostSelector) better(a *host, b *host, now time.Time) bool {
+	aHealthy, bHealthy := a.healthy(now), b.healthy(now)
+	if aHealthy != bHealthy {
+		return aHealthy
+	}
+	if !aHealthy {
+		// Of two failed hosts, try the one that failed longest ago.
+		return a.failedAt.Before(b.failedAt)
+	}
+	if a.latency == 0 || b.latency == 0 {
+		// Unprobed hosts keep their configured order.
+		return false
+	}
+	return a.latency < b.latency
+}
+
+func (s *hostSelector) markFailed(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, h := range s.hosts {
+		if h.url == url {
+			h.failedAt = time.Now()
+		}
+	}
+}
+
+func (s *hostSelector) status() []HostStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	hosts := append([]*host{}, s.hosts...)
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return s.better(hosts[i], hosts[j], now)
+	})
+	status := []HostStatus{}
+	for _, h := range hosts {
+		status = append(status, HostStatus{
+			Url:     h.url,
+			Latency: h.latency,
+			Healthy: h.healthy(now),
+		})
+	}
+	return status
+}
+
+// CurrentBaseUrl returns the root URL requests are currently sent to.
+func (c *RestClient) CurrentBaseUrl() string {
+	return c.hosts.next(nil)
+}
+
+// HostStatus returns the state of each configured host, the preferred host
+// first.
+func (c *RestClient) HostStatus() []HostStatus {
+	return c.hosts.status()
+}
+
+// ProbeHosts measures the latency of each configured host with a ping
+// request, marking hosts that fail as unhealthy.
+func (c *RestClient) ProbeHosts(ctx context.Context) {
+	c.hosts.mu.Lock()
+	hosts := append([]*host{}, c.hosts.hosts...)
+	c.hosts.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, h := range hosts {
+		wg.Add(1)
+		go func(h *host) {
+			defer wg.Done()
+			latency, err := c.probeHost(ctx, h.url)
+			c.hosts.mu.Lock()
+			if err != nil {
+				h.failedAt = time.Now()
+			} else {
+				h.latency = latency
+				h.failedAt = time.Time{}
+			}
+			c.hosts.mu.Unlock()
+		}(h)
+	}
+	wg.Wait()
+}
+
+func (c *RestClient) probeHost(ctx context.Context, baseUrl string) (time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", baseUrl+"/api/v3/ping", nil)
+	if err != nil {
+		return 0, err
+	}
+	c.addHeaders(request)
+	start := time.Now()
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	closeResponse(response)
+	latency := time.Since(start)
+	c.rateLimiter.update(response, c.serverNow())
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("ping failed: %s", response.Status)
+	}
+	return latency, nil
+}
+
+// maybeProbeHosts starts probing the hosts in the background if there is
+// more than one and the probe interval has passed.
+func (c *RestClient) maybeProbeHosts() {
+	s := c.hosts
+	s.mu.Lock()
+	if len(s.hosts) < 2 || c.hostProbeInterval <= 0 || s.probing ||
+		time.Since(s.lastProbe) < c.hostProbeInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.probing = true
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		c.ProbeHosts(ctx)
+		s.mu.Lock()
+		s.probing = false
+		s.lastProbe = time.Now()
+		s.mu.Unlock()
+	}()
+}
+
+// isHostFailure returns true if the host could not be reached or returned a
+// server error.
+func isHostFailure(response *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr) || isTransientError(err)
+	}
+	return response.StatusCode >= 500
+}
+
+// canFailover returns true if a request that failed with err, or a server
+// error if nil, may be sent to another host. Only idempotent requests are
+// sent again unless the connection was never made.
+func canFailover(method string, err error) bool {
+	if method == "GET" {
+		return true
+	}
+	var opErr *net.OpError
+	return err != nil && errors.As(err, &opErr) && opErr.Op == "dial"
+}
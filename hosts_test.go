@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+// testHost is a REST host that answers pings and price tickers, or fails
+// every request with a 503 while failing is set.
+type testHost struct {
+	*httptest.Server
+	pingDelay time.Duration
+	failing   int32
+	requests  int32
+}
+
+func newTestHost(pingDelay time.Duration) *testHost {
+	h := &testHost{pingDelay: pingDelay}
+	h.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&h.requests, 1)
+		if atomic.LoadInt32(&h.failing) != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/api/v3/ping" {
+			time.Sleep(h.pingDelay)
+			w.Write([]byte("{}"))
+			return
+		}
+		w.Write([]byte(`{"symbol":"BTCUSDT","price":"50000.00"}`))
+	}))
+	return h
+}
+
+// closedUrl returns the URL of a server that is no longer listening.
+func closedUrl() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL
+}
+
+func TestFailoverOnGet(t *testing.T) {
+	failing := newTestHost(0)
+	defer failing.Close()
+	atomic.StoreInt32(&failing.failing, 1)
+	good := newTestHost(0)
+	defer good.Close()
+
+	for _, first := range []string{closedUrl(), failing.URL} {
+		client := binanceapi.NewRestClient(
+			binanceapi.WithBaseUrls(first, good.URL),
+			binanceapi.WithHostProbeInterval(0))
+		if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+			t.Fatalf("%s: %v", first, err)
+		}
+		status := client.HostStatus()
+		if status[0].Url != good.URL || !status[0].Healthy {
+			t.Errorf("%s: expected the good host first, got %+v", first, status)
+		}
+		if status[1].Url != first || status[1].Healthy {
+			t.Errorf("%s: expected the failed host to be unhealthy, got %+v", first, status)
+		}
+	}
+	if n := atomic.LoadInt32(&failing.requests); n != 1 {
+		t.Errorf("expected 1 request to the failing host, got %d", n)
+	}
+}
+
+func TestNoFailoverOnPostAfterSending(t *testing.T) {
+	failing := newTestHost(0)
+	defer failing.Close()
+	atomic.StoreInt32(&failing.failing, 1)
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	server.SetBalance("USDT", 1000)
+
+	order := binanceapi.OrderParameters{
+		Symbol:   "BTCUSDT",
+		Side:     binanceapi.OrderSideBuy,
+		Type:     binanceapi.OrderTypeMarket,
+		Quantity: 0.001,
+	}
+
+	// The order may have been placed by the failing host, so it is not sent
+	// again.
+	client := server.Client(
+		binanceapi.WithBaseUrls(failing.URL, server.URL),
+		binanceapi.WithHostProbeInterval(0),
+		binanceapi.WithTimeSync(0))
+	_, err := client.PostOrder(order)
+	var apiErr *binanceapi.RestApiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the 503 of the failing host, got %v", err)
+	}
+	if n := countRequests(server, "/api/v3/order"); n != 0 {
+		t.Errorf("expected no order on the second host, got %d", n)
+	}
+
+	// A host that could not be connected to never saw the order.
+	client = server.Client(
+		binanceapi.WithBaseUrls(closedUrl(), server.URL),
+		binanceapi.WithHostProbeInterval(0),
+		binanceapi.WithTimeSync(0))
+	if _, err := client.PostOrder(order); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(server, "/api/v3/order"); n != 1 {
+		t.Errorf("expected 1 order on the second host, got %d", n)
+	}
+}
+
+func TestHostStatusAndRecovery(t *testing.T) {
+	slow := newTestHost(50 * time.Millisecond)
+	defer slow.Close()
+	fast := newTestHost(0)
+	defer fast.Close()
+
+	client := binanceapi.NewRestClient(
+		binanceapi.WithBaseUrls(slow.URL, fast.URL),
+		binanceapi.WithHostProbeInterval(10*time.Millisecond))
+
+	// Before probing, the hosts keep the order they were given in.
+	if status := client.HostStatus(); status[0].Url != slow.URL || status[0].Latency != 0 {
+		t.Errorf("expected the unprobed hosts in configured order, got %+v", status)
+	}
+
+	client.ProbeHosts(context.Background())
+	status := client.HostStatus()
+	if status[0].Url != fast.URL || status[1].Url != slow.URL {
+		t.Errorf("expected the fast host first, got %+v", status)
+	}
+	if status[1].Latency < 50*time.Millisecond || status[0].Latency >= status[1].Latency {
+		t.Errorf("unexpected latencies %+v", status)
+	}
+	if client.CurrentBaseUrl() != fast.URL {
+		t.Errorf("expected requests to go to the fast host, got %s", client.CurrentBaseUrl())
+	}
+
+	// A failed probe moves a host last, and a later probe brings it back
+	// before the failure cooldown is over.
+	atomic.StoreInt32(&fast.failing, 1)
+	client.ProbeHosts(context.Background())
+	if status := client.HostStatus(); status[0].Url != slow.URL || status[1].Healthy {
+		t.Errorf("expected the failed host last, got %+v", status)
+	}
+
+	atomic.StoreInt32(&fast.failing, 0)
+	deadline := time.Now().Add(2 * time.Second)
+	for client.CurrentBaseUrl() != fast.URL {
+		if time.Now().After(deadline) {
+			t.Fatalf("host did not recover, got %+v", client.HostStatus())
+		}
+		// Requests start a probe once the probe interval has passed.
+		if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
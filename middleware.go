@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// RestCall is a single HTTP request made by a RestClient, as seen by
+// middleware.
+type RestCall struct {
+	// The endpoint path, eg. /api/v3/order.
+	Endpoint string
+
+	Security SecurityType
+
+	// The outgoing request. The API key header and signature are only
+	// added after all middleware has run, so they never appear here.
+	Request *http.Request
+}
+
+// RoundTripFunc sends a RestCall and returns the response.
+type RoundTripFunc func(call *RestCall) (*http.Response, error)
+
+// Middleware wraps the sending of each HTTP request made by a RestClient,
+// including each retry. It may inspect or modify the request before calling
+// next, and inspect the response or error after.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middleware to the client. The first middleware given
+// is the outermost, seeing the request first and the response last.
+func WithMiddleware(middleware ...Middleware) RestClientOption {
+	return func(c *RestClient) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// CallObserver is called after a RestCall completes with either the
+// response or error and the time taken. The response body must not be read.
+type CallObserver func(call *RestCall, response *http.Response, err error, latency time.Duration)
+
+// NewObserverMiddleware returns middleware that calls observer after each
+// request, for use in metrics, tracing or audit trails.
+func NewObserverMiddleware(observer CallObserver) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(call *RestCall) (*http.Response, error) {
+			start := time.Now()
+			response, err := next(call)
+			observer(call, response, err, time.Since(start))
+			return response, err
+		}
+	}
+}
+
+// NewLoggingMiddleware returns middleware that logs each request to logger,
+// or the standard logger if nil.
+func NewLoggingMiddleware(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return NewObserverMiddleware(func(call *RestCall, response *http.Response, err error, latency time.Duration) {
+		if err != nil {
+			logger.Printf("%s %s [%s]: error: %v (%v)", call.Request.Method,
+				RedactUrl(call.Request.URL.String()), call.Security, err, latency)
+			return
+		}
+		logger.Printf("%s %s [%s]: %s (%v)", call.Request.Method,
+			RedactUrl(call.Request.URL.String()), call.Security, response.Status, latency)
+	})
+}
+
+// roundTrip sends call through the middleware chain.
+func (c *RestClient) roundTrip(call *RestCall) (*http.Response, error) {
+	next := RoundTripFunc(c.do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next(call)
+}
@@ -0,0 +1,174 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+// tracingMiddleware appends to trace when a request enters and a response
+// leaves it.
+func tracingMiddleware(name string, trace *[]string) binanceapi.Middleware {
+	return func(next binanceapi.RoundTripFunc) binanceapi.RoundTripFunc {
+		return func(call *binanceapi.RestCall) (*http.Response, error) {
+			*trace = append(*trace, name+" in")
+			response, err := next(call)
+			*trace = append(*trace, name+" out")
+			return response, err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+
+	var trace []string
+	client := server.Client(
+		binanceapi.WithMiddleware(tracingMiddleware("a", &trace), tracingMiddleware("b", &trace)),
+		binanceapi.WithMiddleware(tracingMiddleware("c", &trace)))
+	if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"a in", "b in", "c in", "c out", "b out", "a out"}
+	if !reflect.DeepEqual(trace, expected) {
+		t.Errorf("expected %v, got %v", expected, trace)
+	}
+
+	// Each retry passes through the middleware again.
+	server.Script("GET", "/api/v3/ticker/price", binanceapitest.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       "Service Unavailable",
+	})
+	trace = nil
+	retryClient := newRetryClient(server).With(binanceapi.WithMiddleware(tracingMiddleware("a", &trace)))
+	if _, err := retryClient.GetPriceTicker("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a in", "a out", "a in", "a out"}; !reflect.DeepEqual(trace, expected) {
+		t.Errorf("expected %v, got %v", expected, trace)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+
+	var trace []string
+	respond := func(next binanceapi.RoundTripFunc) binanceapi.RoundTripFunc {
+		return func(call *binanceapi.RestCall) (*http.Response, error) {
+			if call.Request.URL.Query().Get("symbol") == "ETHUSDT" {
+				return nil, errors.New("blocked")
+			}
+			return &http.Response{
+				Status:     "200 OK",
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"symbol":"BTCUSDT","price":"1.5"}`)),
+				Request:    call.Request,
+			}, nil
+		}
+	}
+	client := server.Client(binanceapi.WithMiddleware(
+		tracingMiddleware("a", &trace), respond, tracingMiddleware("b", &trace)))
+
+	ticker, err := client.GetPriceTicker("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticker.Price != 1.5 {
+		t.Errorf("expected the response of the middleware, got %+v", ticker)
+	}
+	if _, err := client.GetPriceTicker("ETHUSDT"); err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Errorf("expected the error of the middleware, got %v", err)
+	}
+
+	// Neither the later middleware nor the server saw the requests.
+	if expected := []string{"a in", "a out", "a in", "a out"}; !reflect.DeepEqual(trace, expected) {
+		t.Errorf("expected %v, got %v", expected, trace)
+	}
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("expected no requests, got %d", n)
+	}
+}
+
+func TestMiddlewareSeesUnsignedRequest(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+
+	var calls []*binanceapi.RestCall
+	var query, apiKey string
+	inspect := func(next binanceapi.RoundTripFunc) binanceapi.RoundTripFunc {
+		return func(call *binanceapi.RestCall) (*http.Response, error) {
+			// The time sync before the first signed request also passes
+			// through middleware.
+			if call.Endpoint != "/api/v3/account" {
+				return next(call)
+			}
+			calls = append(calls, call)
+			query = call.Request.URL.RawQuery
+			apiKey = call.Request.Header.Get("X-MBX-APIKEY")
+			// Parameters added here are sent and signed.
+			values := call.Request.URL.Query()
+			values.Set("omitZeroBalances", "true")
+			call.Request.URL.RawQuery = values.Encode()
+			return next(call)
+		}
+	}
+	client := server.Client(binanceapi.WithMiddleware(inspect))
+
+	if _, err := client.GetAccount(); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	call := calls[0]
+	if call.Security != binanceapi.SecurityTypeUserData {
+		t.Errorf("unexpected security type %v", call.Security)
+	}
+	if !strings.Contains(query, "timestamp=") || !strings.Contains(query, "recvWindow=") {
+		t.Errorf("expected the timestamp and recvWindow, got %q", query)
+	}
+	if strings.Contains(query, "signature=") || apiKey != "" {
+		t.Errorf("middleware saw the signature or API key: %q %q", query, apiKey)
+	}
+
+	requests := server.Requests()
+	if got := requests[len(requests)-1].Params.Get("omitZeroBalances"); got != "true" {
+		t.Errorf("expected the parameter added by middleware, got %q", got)
+	}
+	// Signing works on a copy, leaving the request of the call unsigned.
+	if strings.Contains(call.Request.URL.RawQuery, "signature=") {
+		t.Errorf("signature added to the request of the call: %q", call.Request.URL.RawQuery)
+	}
+}
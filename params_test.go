@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+)
+
+func TestBuildQueryString(t *testing.T) {
+	tests := []struct {
+		params   map[string]interface{}
+		expected string
+	}{
+		{nil, ""},
+		{map[string]interface{}{"symbol": "BTCUSDT", "limit": 5}, "limit=5&symbol=BTCUSDT"},
+		{map[string]interface{}{"newClientOrderId": "a b&c=d"}, "newClientOrderId=a+b%26c%3Dd"},
+		{map[string]interface{}{"quantity": 1e-8}, "quantity=0.00000001"},
+		{map[string]interface{}{"price": float32(0.1)}, "price=0.1"},
+		{map[string]interface{}{"quantity": 1e21}, "quantity=1000000000000000000000"},
+		{map[string]interface{}{"timestamp": int64(1499827319559)}, "timestamp=1499827319559"},
+		{map[string]interface{}{"isIsolated": true}, "isIsolated=true"},
+		{
+			map[string]interface{}{"symbols": []string{"BTCUSDT", "ETHUSDT"}},
+			"symbols=%5B%22BTCUSDT%22%2C%22ETHUSDT%22%5D",
+		},
+		{
+			map[string]interface{}{"orderIdList": []int64{1, 2}},
+			"orderIdList=%5B1%2C2%5D",
+		},
+	}
+
+	client := binanceapi.NewRestClient()
+	for _, test := range tests {
+		if actual := client.BuildQueryString(test.params); actual != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.params, test.expected, actual)
+		}
+	}
+}
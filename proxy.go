@@ -51,12 +51,13 @@ func BinanceApiProxy(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	response, err := http.DefaultClient.Do(request)
+	response, err := defaultHttpClient.Do(request)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		log.Printf("error: failed to send request: %v\n", err)
 		return
 	}
+	defer response.Body.Close()
 
 	for key, val := range response.Header {
 		w.Header()[key] = val
@@ -0,0 +1,455 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RateLimitTypeRequestWeight = "REQUEST_WEIGHT"
+	RateLimitTypeOrders        = "ORDERS"
+	RateLimitTypeRawRequests   = "RAW_REQUESTS"
+)
+
+// RateLimit is a limit as published in ExchangeInfoResponse.RateLimits.
+type RateLimit struct {
+	RateLimitType     string `json:"rateLimitType"`
+	RateLimitInterval string `json:"rateLimitInterval"`
+	IntervalNum       int64  `json:"intervalNum"`
+	Limit             int64  `json:"limit"`
+}
+
+// Duration returns the length of the interval the limit applies to.
+func (r RateLimit) Duration() time.Duration {
+	num := r.IntervalNum
+	if num == 0 {
+		num = 1
+	}
+	switch r.RateLimitInterval {
+	case "SECOND":
+		return time.Duration(num) * time.Second
+	case "MINUTE":
+		return time.Duration(num) * time.Minute
+	case "HOUR":
+		return time.Duration(num) * time.Hour
+	case "DAY":
+		return time.Duration(num) * 24 * time.Hour
+	}
+	return 0
+}
+
+// The limits used until they are replaced with those from exchange info.
+var defaultRateLimits = []RateLimit{
+	{RateLimitTypeRequestWeight, "MINUTE", 1, 6000},
+	{RateLimitTypeOrders, "SECOND", 10, 100},
+	{RateLimitTypeOrders, "DAY", 1, 200000},
+}
+
+// RateLimitMode controls what the client does before sending a request that
+// would exceed a rate limit. In every mode, requests fail with a
+// *RateLimitError without being sent while the server has asked the client
+// to back off with the Retry-After of a 429 or 418 response.
+type RateLimitMode int
+
+const (
+	// Only track usage, requests exceeding the local limits are sent.
+	RateLimitModeTrack RateLimitMode = iota
+
+	// Wait until the interval resets before sending the request.
+	RateLimitModeBlock
+
+	// Return a *RateLimitError without sending the request.
+	RateLimitModeFailFast
+)
+
+// WithRateLimitMode sets how the client reacts to a request that would
+// exceed a rate limit. Defaults to RateLimitModeTrack, which sends such
+// requests and only fails them during a backoff requested by the server.
+func WithRateLimitMode(mode RateLimitMode) RestClientOption {
+	return func(c *RestClient) {
+		c.rateLimitMode = mode
+	}
+}
+
+// RateLimitUsage is the usage of a rate limit in its current interval.
+type RateLimitUsage struct {
+	RateLimitType string
+	Interval      time.Duration
+	Used          int64
+	Limit         int64
+}
+
+// RateLimitError is returned in RateLimitModeFailFast when sending a request
+// would exceed a rate limit, or the client has been told to back off.
+type RateLimitError struct {
+	RateLimitType string
+	Interval      time.Duration
+	Used          int64
+	Limit         int64
+
+	// How long until the request could be sent.
+	Wait time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RateLimitType == "" {
+		return fmt.Sprintf("rate limited by server, retry in %v", e.Wait)
+	}
+	return fmt.Sprintf("rate limit %s/%v would be exceeded (%d/%d), retry in %v",
+		e.RateLimitType, e.Interval, e.Used, e.Limit, e.Wait)
+}
+
+// How long to back off after a 418 without a Retry-After, the shortest IP
+// ban Binance gives.
+const defaultBanBackoff = 2 * time.Minute
+
+type rateLimitKey struct {
+	rateLimitType string
+	interval      time.Duration
+}
+
+type rateLimitCounter struct {
+	used   int64
+	limit  int64
+	window time.Time
+}
+
+type rateLimiter struct {
+	mu       sync.Mutex
+	counters map[rateLimitKey]*rateLimitCounter
+
+	// Set from the Retry-After header of a 429 or 418 response.
+	backoffUntil time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	l := &rateLimiter{
+		counters: map[rateLimitKey]*rateLimitCounter{},
+	}
+	l.setLimits(defaultRateLimits)
+	return l
+}
+
+// counter returns the counter for key, resetting it if now is past its
+// window. Binance windows are aligned to the interval, ie. the minute limit
+// resets at the start of every minute.
+func (l *rateLimiter) counter(key rateLimitKey, now time.Time) *rateLimitCounter {
+	counter, ok := l.counters[key]
+	if !ok {
+		counter = &rateLimitCounter{}
+		l.counters[key] = counter
+	}
+	window := now.Truncate(key.interval)
+	if !counter.window.Equal(window) {
+		counter.window = window
+		counter.used = 0
+	}
+	return counter
+}
+
+func (l *rateLimiter) setLimits(limits []RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, limit := range limits {
+		interval := limit.Duration()
+		if interval == 0 {
+			continue
+		}
+		key := rateLimitKey{limit.RateLimitType, interval}
+		counter, ok := l.counters[key]
+		if !ok {
+			counter = &rateLimitCounter{}
+			l.counters[key] = counter
+		}
+		counter.limit = limit.Limit
+	}
+}
+
+// reserve accounts for a request of the given weight and order count,
+// waiting or failing first as mode requires. While the server has asked the
+// client to back off with a 429 or 418 response, requests fail in every
+// mode, as sending more escalates an IP ban.
+func (l *rateLimiter) reserve(ctx context.Context, mode RateLimitMode, now func() time.Time, weight int64, orders int64) error {
+	for {
+		l.mu.Lock()
+		if err := l.checkBackoff(now()); err != nil {
+			l.mu.Unlock()
+			return err
+		}
+		err := l.check(now(), weight, orders)
+		if err == nil || mode == RateLimitModeTrack {
+			l.add(now(), weight, orders)
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if mode == RateLimitModeFailFast {
+			return err
+		}
+
+		timer := time.NewTimer(err.Wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// checkBackoff returns an error if now is before the end of a backoff
+// requested by the server.
+func (l *rateLimiter) checkBackoff(now time.Time) *RateLimitError {
+	if now.Before(l.backoffUntil) {
+		return &RateLimitError{Wait: l.backoffUntil.Sub(now)}
+	}
+	return nil
+}
+
+// check returns an error if the request would exceed a known limit.
+func (l *rateLimiter) check(now time.Time, weight int64, orders int64) *RateLimitError {
+	for key := range l.counters {
+		counter := l.counter(key, now)
+		var n int64
+		switch key.rateLimitType {
+		case RateLimitTypeRequestWeight:
+			n = weight
+		case RateLimitTypeOrders:
+			n = orders
+		}
+		if n == 0 || counter.limit == 0 || counter.used+n <= counter.limit {
+			continue
+		}
+		return &RateLimitError{
+			RateLimitType: key.rateLimitType,
+			Interval:      key.interval,
+			Used:          counter.used,
+			Limit:         counter.limit,
+			Wait:          counter.window.Add(key.interval).Sub(now),
+		}
+	}
+	return nil
+}
+
+func (l *rateLimiter) add(now time.Time, weight int64, orders int64) {
+	for key := range l.counters {
+		counter := l.counter(key, now)
+		switch key.rateLimitType {
+		case RateLimitTypeRequestWeight:
+			counter.used += weight
+		case RateLimitTypeOrders:
+			counter.used += orders
+		}
+	}
+}
+
+// update replaces the locally accounted usage with the usage reported by the
+// server in the response headers.
+func (l *rateLimiter) update(response *http.Response, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for name, values := range response.Header {
+		if len(values) == 0 {
+			continue
+		}
+		name = strings.ToUpper(name)
+		var rateLimitType string
+		switch {
+		case strings.HasPrefix(name, "X-MBX-USED-WEIGHT-"):
+			rateLimitType = RateLimitTypeRequestWeight
+		case strings.HasPrefix(name, "X-MBX-ORDER-COUNT-"):
+			rateLimitType = RateLimitTypeOrders
+		default:
+			continue
+		}
+		interval := parseRateLimitInterval(name[strings.LastIndex(name, "-")+1:])
+		if interval == 0 {
+			continue
+		}
+		used, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		l.counter(rateLimitKey{rateLimitType, interval}, now).used = used
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusTeapot:
+		if seconds, err := strconv.ParseInt(response.Header.Get("Retry-After"), 10, 64); err == nil {
+			l.backoffUntil = now.Add(time.Duration(seconds) * time.Second)
+		} else if response.StatusCode == http.StatusTeapot {
+			l.backoffUntil = now.Add(defaultBanBackoff)
+		}
+	}
+}
+
+func (l *rateLimiter) usage(now time.Time) []RateLimitUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	usage := []RateLimitUsage{}
+	for key := range l.counters {
+		counter := l.counter(key, now)
+		usage = append(usage, RateLimitUsage{
+			RateLimitType: key.rateLimitType,
+			Interval:      key.interval,
+			Used:          counter.used,
+			Limit:         counter.limit,
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].RateLimitType != usage[j].RateLimitType {
+			return usage[i].RateLimitType > usage[j].RateLimitType
+		}
+		return usage[i].Interval < usage[j].Interval
+	})
+	return usage
+}
+
+// Parse the interval suffix of a rate limit header, eg. 1M or 10S.
+func parseRateLimitInterval(s string) time.Duration {
+	if len(s) < 2 {
+		return 0
+	}
+	num, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	switch s[len(s)-1] {
+	case 'S':
+		return time.Duration(num) * time.Second
+	case 'M':
+		return time.Duration(num) * time.Minute
+	case 'H':
+		return time.Duration(num) * time.Hour
+	case 'D':
+		return time.Duration(num) * 24 * time.Hour
+	}
+	return 0
+}
+
+// endpointWeight returns the request weight Binance charges for a request.
+func endpointWeight(method string, endpoint string, params map[string]interface{}) int64 {
+	_, hasSymbol := params["symbol"]
+	switch endpoint {
+	case "/api/v1/exchangeInfo", "/api/v3/exchangeInfo":
+		return 20
+	case "/api/v3/ticker/price", "/api/v3/ticker/bookTicker":
+		if hasSymbol {
+			return 2
+		}
+		return 4
+	case "/api/v1/userDataStream", "/api/v3/userDataStream":
+		return 2
+	case "/api/v3/order":
+		if method == "GET" {
+			return 4
+		}
+		return 1
+	case "/api/v3/myTrades", "/api/v3/account":
+		return 20
+	case "/api/v3/trades", "/api/v3/historicalTrades":
+		return 25
+	case "/api/v3/aggTrades", "/api/v3/klines", "/api/v3/uiKlines":
+		return 2
+	case "/api/v3/ticker/24hr":
+		if hasSymbol {
+			return 2
+		}
+		symbols, ok := params["symbols"].([]string)
+		switch {
+		case !ok:
+			return 80
+		case len(symbols) <= 20:
+			return 2
+		case len(symbols) <= 100:
+			return 40
+		}
+		return 80
+	case "/api/v3/depth":
+		limit := paramInt(params, "limit", 100)
+		switch {
+		case limit <= 100:
+			return 5
+		case limit <= 500:
+			return 25
+		case limit <= 1000:
+			return 50
+		}
+		return 250
+	}
+	return 1
+}
+
+// paramInt returns the integer value of a parameter, or def if it is not set
+// or not an integer.
+func paramInt(params map[string]interface{}, key string, def int64) int64 {
+	switch v := params[key].(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// endpointOrders returns the number of orders a request counts towards the
+// order rate limits.
+func endpointOrders(method string, endpoint string) int64 {
+	if method == "POST" && endpoint == "/api/v3/order" {
+		return 1
+	}
+	return 0
+}
+
+// RateLimitUsage returns the usage of each known rate limit in its current
+// interval, as last reported by the server or accounted locally.
+func (c *RestClient) RateLimitUsage() []RateLimitUsage {
+	return c.rateLimiter.usage(c.serverNow())
+}
+
+// SetRateLimits replaces the limits checked before sending a request, usually
+// with ExchangeInfoResponse.RateLimits. GetExchangeInfo does this
+// automatically.
+func (c *RestClient) SetRateLimits(limits []RateLimit) {
+	c.rateLimiter.setLimits(limits)
+}
+
+func (c *RestClient) reserveRateLimit(ctx context.Context, method string, endpoint string, params map[string]interface{}) error {
+	return c.rateLimiter.reserve(ctx, c.rateLimitMode, c.serverNow,
+		endpointWeight(method, endpoint, params), endpointOrders(method, endpoint))
+}
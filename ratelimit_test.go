@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func TestServerBackoffFailsInEveryMode(t *testing.T) {
+	modes := []binanceapi.RateLimitMode{
+		binanceapi.RateLimitModeTrack,
+		binanceapi.RateLimitModeBlock,
+		binanceapi.RateLimitModeFailFast,
+	}
+	for _, mode := range modes {
+		server := binanceapitest.NewServer()
+		response := binanceapitest.ErrorResponse(http.StatusTeapot, -1003, "Way too many requests; IP banned.")
+		response.Header = http.Header{"Retry-After": []string{"120"}}
+		server.Script("GET", "/api/v3/ticker/price", response)
+
+		client := server.Client(binanceapi.WithRateLimitMode(mode))
+		if _, err := client.GetPriceTicker("BTCUSDT"); !binanceapi.IsIPBanned(err) {
+			t.Errorf("mode %d: expected IP ban, got %v", mode, err)
+		}
+		for i := 0; i < 3; i++ {
+			_, err := client.GetPriceTicker("BTCUSDT")
+			var rateLimitErr *binanceapi.RateLimitError
+			if !errors.As(err, &rateLimitErr) {
+				t.Errorf("mode %d: expected *RateLimitError, got %v", mode, err)
+			}
+		}
+		if n := countRequests(server, "/api/v3/ticker/price"); n != 1 {
+			t.Errorf("mode %d: expected 1 request to the server, got %d", mode, n)
+		}
+		server.Close()
+	}
+}
+
+func TestLocalLimits(t *testing.T) {
+	limits := []binanceapi.RateLimit{
+		{RateLimitType: "REQUEST_WEIGHT", RateLimitInterval: "DAY", IntervalNum: 1, Limit: 3},
+	}
+
+	server := binanceapitest.NewServer()
+	defer server.Close()
+
+	// A price ticker has a weight of 2, so only one fits in the limit.
+	client := server.Client(binanceapi.WithRateLimitMode(binanceapi.RateLimitModeFailFast))
+	client.SetRateLimits(limits)
+	if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetPriceTicker("BTCUSDT"); !binanceapi.IsRateLimited(err) {
+		t.Errorf("expected a rate limit error, got %v", err)
+	}
+
+	// Track mode only tracks the local limits.
+	client = server.Client()
+	client.SetRateLimits(limits)
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetPriceTicker("BTCUSDT"); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+}
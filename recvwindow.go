@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// The recvWindow used by signed requests unless changed with
+	// WithRecvWindow or RecvWindow.
+	DefaultRecvWindow = 5 * time.Second
+
+	// The largest recvWindow accepted by Binance.
+	MaxRecvWindow = 60 * time.Second
+)
+
+// WithRecvWindow sets the default recvWindow of signed requests, the time
+// after the request timestamp until the server will no longer accept the
+// request. Binance accepts up to MaxRecvWindow with microsecond precision.
+func WithRecvWindow(recvWindow time.Duration) RestClientOption {
+	return func(c *RestClient) {
+		c.recvWindow = recvWindow
+	}
+}
+
+// RecvWindow overrides the recvWindow of the client for a single signed
+// request. A recvWindow request parameter, in milliseconds, takes precedence
+// over both and is validated the same way.
+func RecvWindow(recvWindow time.Duration) RequestOption {
+	return func(r *restRequest) {
+		r.recvWindow = recvWindow
+	}
+}
+
+// ValidateRecvWindow returns an error if recvWindow is not accepted by
+// Binance.
+func ValidateRecvWindow(recvWindow time.Duration) error {
+	if recvWindow <= 0 || recvWindow > MaxRecvWindow {
+		return fmt.Errorf("recvWindow %v not in range (0, %v]", recvWindow, MaxRecvWindow)
+	}
+	if recvWindow%time.Microsecond != 0 {
+		return fmt.Errorf("recvWindow %v has more than microsecond precision", recvWindow)
+	}
+	return nil
+}
+
+// paramRecvWindow returns the recvWindow given as a request parameter, in
+// milliseconds unless it is a time.Duration.
+func paramRecvWindow(value interface{}) (time.Duration, error) {
+	if recvWindow, ok := value.(time.Duration); ok {
+		return recvWindow, nil
+	}
+	millis, err := strconv.ParseFloat(formatParam(value), 64)
+	if err != nil || math.IsNaN(millis) || math.IsInf(millis, 0) {
+		return 0, fmt.Errorf("invalid recvWindow %q", formatParam(value))
+	}
+	return time.Duration(math.Round(millis * float64(time.Millisecond))), nil
+}
+
+// formatRecvWindow formats recvWindow in milliseconds with up to three
+// decimal places, eg. 5000 or 6000.346.
+func formatRecvWindow(recvWindow time.Duration) string {
+	millis := int64(recvWindow / time.Millisecond)
+	micros := int64(recvWindow % time.Millisecond / time.Microsecond)
+	if micros == 0 {
+		return strconv.FormatInt(millis, 10)
+	}
+	return strings.TrimRight(fmt.Sprintf("%d.%03d", millis, micros), "0")
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func TestValidateRecvWindow(t *testing.T) {
+	valid := []time.Duration{time.Microsecond, binanceapi.DefaultRecvWindow,
+		6*time.Second + 346*time.Microsecond, binanceapi.MaxRecvWindow}
+	for _, recvWindow := range valid {
+		if err := binanceapi.ValidateRecvWindow(recvWindow); err != nil {
+			t.Errorf("%v: %v", recvWindow, err)
+		}
+	}
+	invalid := []time.Duration{0, -time.Second, binanceapi.MaxRecvWindow + time.Microsecond,
+		time.Second + time.Nanosecond}
+	for _, recvWindow := range invalid {
+		if err := binanceapi.ValidateRecvWindow(recvWindow); err == nil {
+			t.Errorf("%v: expected an error", recvWindow)
+		}
+	}
+}
+
+func TestRecvWindowSent(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client(binanceapi.WithRecvWindow(6*time.Second + 346*time.Microsecond))
+
+	tests := []struct {
+		params   map[string]interface{}
+		options  []binanceapi.RequestOption
+		expected string
+	}{
+		{nil, nil, "6000.346"},
+		{nil, []binanceapi.RequestOption{binanceapi.RecvWindow(7 * time.Second)}, "7000"},
+		{map[string]interface{}{"recvWindow": 8000}, nil, "8000"},
+		{map[string]interface{}{"recvWindow": "8000.5"}, nil, "8000.5"},
+		{map[string]interface{}{"recvWindow": 9 * time.Second}, nil, "9000"},
+	}
+	for _, test := range tests {
+		var account binanceapi.AccountInfoResponse
+		if err := client.AuthGetAndDecode("/api/v3/account", test.params, &account, test.options...); err != nil {
+			t.Fatal(err)
+		}
+		requests := server.Requests()
+		if got := requests[len(requests)-1].Params.Get("recvWindow"); got != test.expected {
+			t.Errorf("params %v: expected recvWindow %s, got %s", test.params, test.expected, got)
+		}
+	}
+}
+
+func TestRecvWindowParamValidated(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	for _, recvWindow := range []interface{}{0, 60001, "5000.0001", "soon", -5 * time.Second} {
+		var account binanceapi.AccountInfoResponse
+		params := map[string]interface{}{"recvWindow": recvWindow}
+		if err := client.AuthGetAndDecode("/api/v3/account", params, &account); err == nil {
+			t.Errorf("recvWindow %v: expected an error", recvWindow)
+		}
+	}
+	if n := countRequests(server, "/api/v3/account"); n != 0 {
+		t.Errorf("expected no requests, got %d", n)
+	}
+}
@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+var signatureParamRegexp = regexp.MustCompile(`(signature=)[^&\s"]*`)
+
+// RedactSecret returns a form of an API key or secret that is safe to log,
+// keeping only the first 4 characters.
+func RedactSecret(secret string) string {
+	if len(secret) <= 8 {
+		return redacted
+	}
+	return secret[:4] + "..." + redacted
+}
+
+// RedactUrl replaces the value of any signature parameter in s.
+func RedactUrl(s string) string {
+	return signatureParamRegexp.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// redactedError is an error with secrets removed from its message. Unwrap
+// gives the redacted form of the wrapped error, so errors.As can not reach
+// the original message.
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with signatures and the given secrets removed from
+// its message and from the errors it wraps. A *url.Error is copied with a
+// redacted URL, keeping its type for errors.As and its Timeout method, and
+// errors with nothing to redact are kept as they are.
+func redactError(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+	redact := func(s string) string {
+		s = RedactUrl(s)
+		for _, secret := range secrets {
+			if secret != "" {
+				s = strings.Replace(s, secret, redacted, -1)
+			}
+		}
+		return s
+	}
+	message := redact(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	if urlErr, ok := err.(*url.Error); ok {
+		return &url.Error{
+			Op:  urlErr.Op,
+			URL: redact(urlErr.URL),
+			Err: redactError(urlErr.Err, secrets...),
+		}
+	}
+	return &redactedError{
+		message: message,
+		err:     redactError(errors.Unwrap(err), secrets...),
+	}
+}
@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+var signatureRegexp = regexp.MustCompile(`signature=([^&\s"]*)`)
+
+// checkRedacted fails the test if s contains a signature that is not
+// redacted.
+func checkRedacted(t *testing.T, what string, s string) {
+	t.Helper()
+	for _, match := range signatureRegexp.FindAllStringSubmatch(s, -1) {
+		if match[1] != "REDACTED" {
+			t.Errorf("%s leaks the signature: %s", what, s)
+		}
+	}
+}
+
+func TestTransportErrorsRedacted(t *testing.T) {
+	// The transport error includes the signed URL, and the http.Client
+	// wraps it in a *url.Error with the signed URL too.
+	transport := roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		return nil, fmt.Errorf("reading %s: %w", request.URL, io.ErrUnexpectedEOF)
+	})
+	client := binanceapi.NewRestClient(
+		binanceapi.WithBaseUrl("https://api.binance.com"),
+		binanceapi.WithHttpClient(&http.Client{Transport: transport}),
+		binanceapi.WithAuth(testApiKey, testSecret),
+		binanceapi.WithTimeSync(0))
+
+	_, err := client.GetAccount()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	checkRedacted(t, "Error", err.Error())
+	checkRedacted(t, "%+v", fmt.Sprintf("%+v", err))
+	checkRedacted(t, "%#v", fmt.Sprintf("%#v", err))
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Fatalf("expected a *url.Error, got %T", err)
+	}
+	checkRedacted(t, "url.Error.URL", urlErr.URL)
+	checkRedacted(t, "url.Error.Err", urlErr.Err.Error())
+	for wrapped := errors.Unwrap(err); wrapped != nil; wrapped = errors.Unwrap(wrapped) {
+		checkRedacted(t, fmt.Sprintf("wrapped %T", wrapped), wrapped.Error())
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected the error to still match io.ErrUnexpectedEOF")
+	}
+}
+
+func TestDialErrorsRedacted(t *testing.T) {
+	client := binanceapi.NewRestClient(
+		binanceapi.WithBaseUrl(closedUrl()),
+		binanceapi.WithAuth(testApiKey, testSecret),
+		binanceapi.WithTimeSync(0))
+
+	_, err := client.GetAccount()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	checkRedacted(t, "Error", err.Error())
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Fatalf("expected a *url.Error, got %T", err)
+	}
+	checkRedacted(t, "url.Error.URL", urlErr.URL)
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "dial" {
+		t.Errorf("expected a dial *net.OpError, got %v", err)
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) {
+		t.Errorf("expected a net.Error, got %T", err)
+	}
+}
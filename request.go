@@ -0,0 +1,400 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SecurityType is the Binance security type of an endpoint, which
+// determines whether a request needs the API key and a signature.
+type SecurityType int
+
+const (
+	// Public endpoint.
+	SecurityTypeNone SecurityType = iota
+
+	// Requires the API key.
+	SecurityTypeMarketData
+
+	// Requires the API key.
+	SecurityTypeUserStream
+
+	// Requires the API key and a signature.
+	SecurityTypeTrade
+
+	// Requires the API key and a signature.
+	SecurityTypeUserData
+)
+
+func (s SecurityType) String() string {
+	switch s {
+	case SecurityTypeNone:
+		return "NONE"
+	case SecurityTypeMarketData:
+		return "MARKET_DATA"
+	case SecurityTypeUserStream:
+		return "USER_STREAM"
+	case SecurityTypeTrade:
+		return "TRADE"
+	case SecurityTypeUserData:
+		return "USER_DATA"
+	}
+	return fmt.Sprintf("SecurityType(%d)", int(s))
+}
+
+// RequiresApiKey returns true if requests must carry the X-MBX-APIKEY header.
+func (s SecurityType) RequiresApiKey() bool {
+	return s != SecurityTypeNone
+}
+
+// RequiresSignature returns true if requests must be timestamped and signed.
+func (s SecurityType) RequiresSignature() bool {
+	return s == SecurityTypeTrade || s == SecurityTypeUserData
+}
+
+type restRequest struct {
+	method   string
+	endpoint string
+	security SecurityType
+	params   map[string]interface{}
+
+	// Overrides the client recvWindow if not 0.
+	recvWindow time.Duration
+
+	// Send params as a form encoded body instead of in the query string.
+	paramsInBody bool
+
+	// Parameters always sent in the query string.
+	queryParams map[string]interface{}
+}
+
+// RequestOption changes how a single request is sent.
+type RequestOption func(r *restRequest)
+
+// ParamsInBody sends the parameters of a POST, PUT or DELETE request,
+// including the signature, as an application/x-www-form-urlencoded body
+// instead of in the query string.
+func ParamsInBody() RequestOption {
+	return func(r *restRequest) {
+		r.paramsInBody = true
+	}
+}
+
+// ParamsInQuery sends the parameters in the query string, overriding
+// WithParamsInBody for a single request.
+func ParamsInQuery() RequestOption {
+	return func(r *restRequest) {
+		r.paramsInBody = false
+	}
+}
+
+// QueryParams adds parameters that are always sent in the query string.
+// With ParamsInBody this sends a mixed request, which is signed over the
+// query string followed by the body.
+func QueryParams(params map[string]interface{}) RequestOption {
+	return func(r *restRequest) {
+		if r.queryParams == nil {
+			r.queryParams = map[string]interface{}{}
+		}
+		for key, val := range params {
+			r.queryParams[key] = val
+		}
+	}
+}
+
+// WithParamsInBody sends the parameters of all POST, PUT and DELETE requests
+// in the body, keeping order details out of URLs and access logs.
+func WithParamsInBody() RestClientOption {
+	return func(c *RestClient) {
+		c.paramsInBody = true
+	}
+}
+
+// Request sends a request to an endpoint with the given security type. The
+// API key header, timestamp and signature are added as the security type
+// requires, and the request is subject to the rate limiter and retry policy.
+// Unsigned GET requests may be answered from the cache set with WithCache.
+//
+// An error status is not turned into an error; use RequestAndDecode for that.
+func (c *RestClient) Request(ctx context.Context, method string, endpoint string, security SecurityType, params map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	r := &restRequest{
+		method:       method,
+		endpoint:     endpoint,
+		security:     security,
+		params:       params,
+		paramsInBody: c.paramsInBody,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	if c.cache != nil && c.cache.cacheable(r) {
+		return c.cache.get(ctx, c.hosts.urls(), r, func(ctx context.Context) (*http.Response, error) {
+			return c.execute(ctx, r)
+		})
+	}
+	return c.execute(ctx, r)
+}
+
+// RequestAndDecode is like Request but returns a *RestApiError for an error
+// status and otherwise decodes the JSON body into response, which may be nil
+// if the body is not needed.
+func (c *RestClient) RequestAndDecode(ctx context.Context, method string, endpoint string, security SecurityType, params map[string]interface{}, response interface{}, options ...RequestOption) error {
+	httpResponse, err := c.Request(ctx, method, endpoint, security, params, options...)
+	if err != nil {
+		return err
+	}
+	defer closeResponse(httpResponse)
+	if httpResponse.StatusCode >= 400 {
+		return NewRestApiErrorFromResponse(httpResponse)
+	}
+	if response == nil {
+		return nil
+	}
+	return c.decodeBody(httpResponse, response)
+}
+
+// execute sends a request according to the retry policy. If Binance rejects
+// the timestamp of a signed request, the offset to the server clock is
+// resynchronised and the request is sent once more.
+func (c *RestClient) execute(ctx context.Context, r *restRequest) (*http.Response, error) {
+	response, err := c.retry(ctx, r.method, func() (*http.Response, error) {
+		return c.send(ctx, r)
+	})
+	if err != nil || response.StatusCode < 400 || !r.security.RequiresSignature() {
+		return response, err
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiErr RestApiError
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Code == ErrorCodeInvalidTimestamp {
+		if err := c.SyncTime(ctx); err == nil {
+			return c.send(ctx, r)
+		}
+	}
+
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return response, nil
+}
+
+// send builds and sends a single attempt of a request to the preferred
+// host, failing over to the other hosts if it can not be reached.
+func (c *RestClient) send(ctx context.Context, r *restRequest) (*http.Response, error) {
+	c.maybeProbeHosts()
+	tried := map[string]bool{}
+	for {
+		baseUrl := c.hosts.next(tried)
+		tried[baseUrl] = true
+
+		response, err := c.sendTo(ctx, r, baseUrl)
+		if !isHostFailure(response, err) {
+			return response, err
+		}
+		c.hosts.markFailed(baseUrl)
+
+		if len(tried) >= c.hosts.len() || !canFailover(r.method, err) {
+			return response, err
+		}
+		if response != nil {
+			closeResponse(response)
+		}
+	}
+}
+
+// closeResponse reads what is left of the body of response and closes it,
+// so the connection can be reused for another request. Long bodies are not
+// worth reading and the connection is closed instead.
+func closeResponse(response *http.Response) {
+	io.CopyN(ioutil.Discard, response.Body, 64*1024)
+	response.Body.Close()
+}
+
+// sendTo builds and sends a request to the host at baseUrl.
+func (c *RestClient) sendTo(ctx context.Context, r *restRequest, baseUrl string) (*http.Response, error) {
+	// Wait for the rate limit before taking the timestamp so a blocked
+	// request is not sent with a stale one.
+	if err := c.reserveRateLimit(ctx, r.method, r.endpoint, r.params); err != nil {
+		return nil, err
+	}
+
+	// Copy the parameters so a retry does not see the values added here.
+	params := map[string]interface{}{}
+	for key, val := range r.params {
+		params[key] = val
+	}
+
+	if r.security.RequiresSignature() {
+		recvWindow := c.recvWindow
+		if r.recvWindow != 0 {
+			recvWindow = r.recvWindow
+		}
+		if value, ok := params["recvWindow"]; ok {
+			var err error
+			if recvWindow, err = paramRecvWindow(value); err != nil {
+				return nil, err
+			}
+		}
+		if err := ValidateRecvWindow(recvWindow); err != nil {
+			return nil, err
+		}
+		params["recvWindow"] = formatRecvWindow(recvWindow)
+		params["timestamp"] = c.timestamp(ctx)
+	}
+
+	queryParams := map[string]interface{}{}
+	for key, val := range r.queryParams {
+		queryParams[key] = val
+	}
+
+	// GET requests can not have a body.
+	body := ""
+	if r.paramsInBody && r.method != "GET" {
+		body = c.BuildQueryString(params)
+	} else {
+		for key, val := range params {
+			queryParams[key] = val
+		}
+	}
+
+	requestUrl := fmt.Sprintf("%s%s", baseUrl, r.endpoint)
+	if queryString := c.BuildQueryString(queryParams); queryString != "" {
+		requestUrl = fmt.Sprintf("%s?%s", requestUrl, queryString)
+	}
+
+	var bodyReader io.Reader
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, r.method, requestUrl, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	c.addHeaders(request)
+
+	return c.roundTrip(&RestCall{
+		Endpoint: r.endpoint,
+		Security: r.security,
+		Request:  request,
+	})
+}
+
+// signRequest adds the signature to request. Binance verifies it against the
+// query string concatenated with the body, and it is sent in the body if
+// there is one.
+func (c *RestClient) signRequest(call *RestCall, request *http.Request, signer Signer) error {
+	if signer == nil {
+		return fmt.Errorf("no signer configured for %s request to %s",
+			call.Security, call.Endpoint)
+	}
+
+	var body []byte
+	if request.GetBody != nil {
+		reader, err := request.GetBody()
+		if err != nil {
+			return err
+		}
+		body, err = ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	signature, err := sign(signer, append([]byte(request.URL.RawQuery), body...))
+	if err != nil {
+		return err
+	}
+
+	if len(body) == 0 {
+		request.URL.RawQuery = appendSignature(request.URL.RawQuery, signature)
+		return nil
+	}
+
+	signedBody := appendSignature(string(body), signature)
+	request.Body = ioutil.NopCloser(strings.NewReader(signedBody))
+	request.ContentLength = int64(len(signedBody))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(signedBody)), nil
+	}
+	return nil
+}
+
+// do sends the request of call after adding the API key header and
+// signature as required by the security type, and records the rate limit
+// usage reported in the response.
+//
+// The request is cloned first, so the key and signature are never visible
+// to middleware, and the response refers back to the unsigned request.
+func (c *RestClient) do(call *RestCall) (*http.Response, error) {
+	ctx := call.Request.Context()
+	request := call.Request.Clone(ctx)
+
+	var credentials Credentials
+	if call.Security.RequiresApiKey() && c.credentials != nil {
+		var err error
+		credentials, err = c.credentials.Credentials(ctx)
+		if err != nil {
+			return nil, redactError(fmt.Errorf("failed to get credentials: %w", err))
+		}
+	}
+
+	if call.Security.RequiresSignature() {
+		if err := c.signRequest(call, request, credentials.Signer); err != nil {
+			return nil, redactError(err, credentials.ApiKey)
+		}
+	}
+
+	if credentials.ApiKey != "" {
+		request.Header.Set("X-MBX-APIKEY", credentials.ApiKey)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		// The error carries the URL, replace it with the unsigned one.
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = call.Request.URL.String()
+		}
+		return nil, redactError(err, credentials.ApiKey)
+	}
+	response.Request = call.Request
+	c.rateLimiter.update(response, c.serverNow())
+	return response, nil
+}
@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+)
+
+func TestParamsInBodyWithQueryParams(t *testing.T) {
+	var rawQuery, body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := ioutil.ReadAll(r.Body)
+		rawQuery, body, contentType = r.URL.RawQuery, string(buf), r.Header.Get("Content-Type")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := binanceapi.NewRestClient(
+		binanceapi.WithBaseUrl(server.URL),
+		binanceapi.WithAuth("key", docsSecret),
+		binanceapi.WithTimeSync(0))
+	err := client.RequestAndDecode(context.Background(), "POST", "/api/v3/order",
+		binanceapi.SecurityTypeTrade,
+		map[string]interface{}{"side": "BUY", "quantity": 1},
+		&map[string]interface{}{},
+		binanceapi.ParamsInBody(),
+		binanceapi.QueryParams(map[string]interface{}{"symbol": "LTCBTC"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rawQuery != "symbol=LTCBTC" {
+		t.Errorf("unexpected query string %q", rawQuery)
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+
+	// The signature is sent last in the body and covers the query string
+	// followed by the rest of the body.
+	i := strings.LastIndex(body, "&signature=")
+	if i < 0 {
+		t.Fatalf("no signature in body %q", body)
+	}
+	signature, err := url.QueryUnescape(body[i+len("&signature="):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := body[:i]
+	if !strings.HasPrefix(unsigned, "quantity=1&recvWindow=5000&side=BUY&timestamp=") {
+		t.Errorf("unexpected body %q", body)
+	}
+	expected, _ := binanceapi.NewHmacSigner(docsSecret).Sign([]byte(rawQuery + unsigned))
+	if signature != expected {
+		t.Errorf("signature %s is not over query and body, expected %s", signature, expected)
+	}
+}
@@ -25,238 +25,275 @@ package binanceapi
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"net/http"
-	"sort"
+	"net/url"
+	"os"
+	"strings"
+	"time"
 )
 
 const API_ROOT = "https://api.binance.com"
 
-type RestClient struct {
-	apiKey    string
-	apiSecret string
-}
+// The base URL used by clients that are not given one with WithBaseUrl.
+var defaultBaseUrl = defaultEnvironment.RestUrl
 
-func NewRestClient() *RestClient {
-	return &RestClient{}
+func init() {
+	envApiUrl := os.Getenv("BINANCE_API_URL")
+	if envApiUrl != "" {
+		log.Printf("Using Binance API URL from environment: %s", envApiUrl)
+		defaultBaseUrl = strings.TrimSuffix(envApiUrl, "/")
+	}
 }
 
-func (c *RestClient) WithAuth(key string, secret string) *RestClient {
-	c.apiKey = key
-	c.apiSecret = secret
-	return c
+// RestClient is a client for the Binance REST API.
+//
+// A RestClient is configured once by NewRestClient and is not changed
+// afterwards, so it is safe for concurrent use by multiple goroutines. To
+// use different credentials or options, derive a new client with With,
+// WithAuth or WithSigner, which leave the original untouched.
+//
+// A derived client shares the state the server tracks per IP address or
+// account with the client it was derived from: the offset to the server
+// clock, rate limit usage, host health and the response cache. A client
+// derived with new base URLs starts with its own clock offset and rate
+// limits.
+type RestClient struct {
+	credentials CredentialProvider
+	hosts       *hostSelector
+	httpClient  *http.Client
+	userAgent   string
+	headers     http.Header
+	timeSync    *timeSync
+	environment Environment
+	recvWindow  time.Duration
+
+	paramsInBody bool
+
+	hostProbeInterval time.Duration
+	timeSyncInterval  time.Duration
+
+	rateLimiter   *rateLimiter
+	rateLimitMode RateLimitMode
+	retryPolicy   RetryPolicy
+	middleware    []Middleware
+	cache         *ResponseCache
 }
 
-// Perform an unauthenticated GET request.
-func (c *RestClient) Get(endpoint string, params map[string]interface{}) (*http.Response, error) {
-
-	url := fmt.Sprintf("%s%s", API_ROOT, endpoint)
-	queryString := ""
+// RestClientOption configures a RestClient when passed to NewRestClient.
+type RestClientOption func(c *RestClient)
 
-	if params == nil {
-		params = map[string]interface{}{}
+// WithBaseUrl sets the root URL requests are sent to, for example the spot
+// testnet or an httptest.Server. Defaults to the RestUrl of
+// DefaultEnvironment, or BINANCE_API_URL if set in the environment.
+func WithBaseUrl(url string) RestClientOption {
+	return func(c *RestClient) {
+		c.hosts = newHostSelector([]string{url})
 	}
+}
 
-	if params != nil {
-		queryString = c.BuildQueryString(params)
-		if queryString != "" {
-			url = fmt.Sprintf("%s?%s", url, queryString)
-		}
+// WithHttpClient sets the http.Client used to send requests. Defaults to a
+// client using NewTransport.
+func WithHttpClient(client *http.Client) RestClientOption {
+	return func(c *RestClient) {
+		c.httpClient = client
 	}
+}
 
-	request, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) RestClientOption {
+	return func(c *RestClient) {
+		c.userAgent = userAgent
 	}
+}
 
-	return http.DefaultClient.Do(request)
+// WithHeader adds a header that will be sent with every request.
+func WithHeader(key string, value string) RestClientOption {
+	return func(c *RestClient) {
+		c.headers.Add(key, value)
+	}
 }
 
-func (c *RestClient) GetWithAuth(endpoint string, params map[string]interface{}) (*http.Response, error) {
+func NewRestClient(options ...RestClientOption) *RestClient {
+	environment, baseUrl, _ := defaultUrls()
+	c := &RestClient{
+		hosts:       newHostSelector([]string{baseUrl}),
+		environment: environment,
+		httpClient:  defaultHttpClient,
+		headers:     http.Header{},
+		timeSync:    &timeSync{},
+		recvWindow:  DefaultRecvWindow,
 
-	url := fmt.Sprintf("%s%s", API_ROOT, endpoint)
-	queryString := ""
+		hostProbeInterval: DefaultHostProbeInterval,
+		timeSyncInterval:  DefaultTimeSyncInterval,
 
-	if params == nil {
-		params = map[string]interface{}{}
+		rateLimiter: newRateLimiter(),
+	}
+	for _, option := range options {
+		option(c)
 	}
+	return c
+}
 
-	params["recvWindow"] = 5000
-	params["timestamp"] = getTimeMillis()
+// WithAuth sets the API key and the secret of an HMAC API key.
+func WithAuth(key string, secret string) RestClientOption {
+	return WithSigner(key, NewHmacSigner(secret))
+}
 
-	if params != nil {
-		queryString = c.BuildQueryString(params)
-		if queryString != "" {
-			url = fmt.Sprintf("%s?%s", url, queryString)
-		}
-	}
+// WithSigner sets the API key and the signer used to sign requests, for
+// example an RsaSigner or Ed25519Signer for the respective API key types.
+func WithSigner(key string, signer Signer) RestClientOption {
+	return WithCredentialProvider(NewStaticCredentials(key, signer))
+}
 
-	mac := hmac.New(sha256.New, []byte(c.apiSecret))
-	mac.Write([]byte(queryString))
-	signature := hex.EncodeToString(mac.Sum(nil))
-	url = fmt.Sprintf("%s&signature=%s",
-		url, signature)
+// Clone returns a copy of the client sharing its clock offset, rate limits,
+// host health and cache.
+func (c *RestClient) Clone() *RestClient {
+	clone := *c
+	clone.headers = c.headers.Clone()
+	clone.middleware = append([]Middleware{}, c.middleware...)
+	return &clone
+}
 
-	request, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+// With returns a copy of the client with options applied. The client itself
+// is not changed.
+func (c *RestClient) With(options ...RestClientOption) *RestClient {
+	clone := c.Clone()
+	for _, option := range options {
+		option(clone)
 	}
+	// The clock offset and rate limits of one host do not apply to another.
+	if clone.hosts != c.hosts {
+		clone.timeSync = &timeSync{}
+		clone.rateLimiter = newRateLimiter()
+	}
+	return clone
+}
 
-	request.Header.Add("X-MBX-APIKEY", c.apiKey)
+// WithAuth returns a copy of the client using the API key and the secret of
+// an HMAC API key. The client itself is not changed.
+func (c *RestClient) WithAuth(key string, secret string) *RestClient {
+	return c.With(WithAuth(key, secret))
+}
 
-	return http.DefaultClient.Do(request)
+// WithSigner returns a copy of the client using the API key and signer. The
+// client itself is not changed.
+func (c *RestClient) WithSigner(key string, signer Signer) *RestClient {
+	return c.With(WithSigner(key, signer))
 }
 
-func (c *RestClient) Post(endpoint string, params map[string]interface{}) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", API_ROOT, endpoint)
-	queryString := ""
+// String describes the client without revealing its credentials.
+// String only includes the type of the credential provider, so a provider
+// without a redacting String method can not leak its secrets.
+func (c *RestClient) String() string {
+	return fmt.Sprintf("RestClient{baseUrl: %s, credentials: %T}",
+		c.CurrentBaseUrl(), c.credentials)
+}
 
-	if params == nil {
-		params = map[string]interface{}{}
-	}
+func (c *RestClient) GoString() string {
+	return c.String()
+}
 
-	params["recvWindow"] = 5000
-	params["timestamp"] = getTimeMillis()
+// Perform an unauthenticated GET request.
+func (c *RestClient) Get(endpoint string, params map[string]interface{}) (*http.Response, error) {
+	return c.GetCtx(context.Background(), endpoint, params)
+}
 
-	if params != nil {
-		queryString = c.BuildQueryString(params)
-		if queryString != "" {
-			url = fmt.Sprintf("%s?%s", url, queryString)
-		}
-	}
+// GetCtx is Get with a context.
+func (c *RestClient) GetCtx(ctx context.Context, endpoint string, params map[string]interface{}) (*http.Response, error) {
+	return c.Request(ctx, "GET", endpoint, SecurityTypeNone, params)
+}
 
-	mac := hmac.New(sha256.New, []byte(c.apiSecret))
-	mac.Write([]byte(queryString))
-	signature := hex.EncodeToString(mac.Sum(nil))
-	url = fmt.Sprintf("%s&signature=%s",
-		url, signature)
+func (c *RestClient) GetWithAuth(endpoint string, params map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	return c.GetWithAuthCtx(context.Background(), endpoint, params, options...)
+}
 
-	request, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *RestClient) GetWithAuthCtx(ctx context.Context, endpoint string, params map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, "GET", endpoint, SecurityTypeUserData, params, options...)
+}
 
-	request.Header.Add("X-MBX-APIKEY", c.apiKey)
+func (c *RestClient) Post(endpoint string, params map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	return c.PostCtx(context.Background(), endpoint, params, options...)
+}
 
-	return http.DefaultClient.Do(request)
+func (c *RestClient) PostCtx(ctx context.Context, endpoint string, params map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, "POST", endpoint, SecurityTypeTrade, params, options...)
 }
 
 // Send a POST request with only the API key and no other authentication.
 func (c *RestClient) PostWithApiKey(endpoint string, params map[string]interface{}) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", API_ROOT, endpoint)
-	queryString := ""
-
-	if params == nil {
-		params = map[string]interface{}{}
-	}
-
-	if params != nil {
-		queryString = c.BuildQueryString(params)
-		if queryString != "" {
-			url = fmt.Sprintf("%s?%s", url, queryString)
-		}
-	}
-
-	request, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if c.apiKey != "" {
-		request.Header.Add("X-MBX-APIKEY", c.apiKey)
-	}
+	return c.PostWithApiKeyCtx(context.Background(), endpoint, params)
+}
 
-	return http.DefaultClient.Do(request)
+// PostWithApiKeyCtx is PostWithApiKey with a context.
+func (c *RestClient) PostWithApiKeyCtx(ctx context.Context, endpoint string, params map[string]interface{}) (*http.Response, error) {
+	return c.Request(ctx, "POST", endpoint, SecurityTypeUserStream, params)
 }
 
 func (c *RestClient) PutWithApiKey(path string) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", API_ROOT, path)
-	request, err := http.NewRequest("PUT", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	request.Header.Add("X-MBX-APIKEY", c.apiKey)
-
-	return http.DefaultClient.Do(request)
+	return c.PutWithApiKeyCtx(context.Background(), path)
 }
 
-func (c *RestClient) Delete(endpoint string, params map[string]interface{}) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", API_ROOT, endpoint)
-	queryString := ""
-
-	if params == nil {
-		params = map[string]interface{}{}
+func (c *RestClient) PutWithApiKeyCtx(ctx context.Context, path string) (*http.Response, error) {
+	endpoint := path
+	params := map[string]interface{}{}
+	if i := strings.Index(path, "?"); i > -1 {
+		endpoint = path[:i]
+		query, err := url.ParseQuery(path[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		for key := range query {
+			params[key] = query.Get(key)
+		}
 	}
+	return c.Request(ctx, "PUT", endpoint, SecurityTypeUserStream, params)
+}
 
-	params["recvWindow"] = 5000
-	params["timestamp"] = getTimeMillis()
+func (c *RestClient) Delete(endpoint string, params map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	return c.DeleteCtx(context.Background(), endpoint, params, options...)
+}
 
-	if params != nil {
-		queryString = c.BuildQueryString(params)
-		if queryString != "" {
-			url = fmt.Sprintf("%s?%s", url, queryString)
+func (c *RestClient) DeleteCtx(ctx context.Context, endpoint string, params map[string]interface{}, options ...RequestOption) (*http.Response, error) {
+	return c.Request(ctx, "DELETE", endpoint, SecurityTypeTrade, params, options...)
+}
+
+// addHeaders adds the default headers and user agent to request.
+func (c *RestClient) addHeaders(request *http.Request) {
+	for key, values := range c.headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
 		}
 	}
-
-	mac := hmac.New(sha256.New, []byte(c.apiSecret))
-	mac.Write([]byte(queryString))
-	signature := hex.EncodeToString(mac.Sum(nil))
-	url = fmt.Sprintf("%s&signature=%s",
-		url, signature)
-
-	request, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return nil, err
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
 	}
-
-	request.Header.Add("X-MBX-APIKEY", c.apiKey)
-
-	return http.DefaultClient.Do(request)
 }
 
+// BuildQueryString encodes params as a query string sorted by key. This is
+// the exact string that is signed for signed requests.
 func (c *RestClient) BuildQueryString(params map[string]interface{}) string {
-	queryString := ""
-
-	keys := func() []string {
-		keys := []string{}
-		for key, _ := range params {
-			keys = append(keys, key)
-		}
-		sort.Strings(keys)
-		return keys
-	}()
+	return encodeParams(params)
+}
 
-	for _, key := range keys {
-		if queryString != "" {
-			queryString = fmt.Sprintf("%s&", queryString)
-		}
-		queryString = fmt.Sprintf("%s%s=%v", queryString, key, params[key])
-	}
+func (c *RestClient) GetAndDecode(endpoint string, params map[string]interface{}, response interface{}) error {
+	return c.GetAndDecodeCtx(context.Background(), endpoint, params, response)
+}
 
-	return queryString
+func (c *RestClient) GetAndDecodeCtx(ctx context.Context, endpoint string, params map[string]interface{}, response interface{}) error {
+	return c.RequestAndDecode(ctx, "GET", endpoint, SecurityTypeNone, params, response)
 }
 
-func (c *RestClient) GetAndDecode(endpoint string, params map[string]interface{}, response interface{}) error {
-	httpResponse, err := c.Get(endpoint, params)
-	if err != nil {
-		return err
-	}
-	defer httpResponse.Body.Close()
-	return c.decodeBody(httpResponse, response)
+func (c *RestClient) AuthGetAndDecode(endpoint string, params map[string]interface{}, response interface{}, options ...RequestOption) error {
+	return c.AuthGetAndDecodeCtx(context.Background(), endpoint, params, response, options...)
 }
 
-func (c *RestClient) AuthGetAndDecode(endpoint string, params map[string]interface{}, response interface{}) error {
-	httpResponse, err := c.Get(endpoint, params)
-	if err != nil {
-		return err
-	}
-	defer httpResponse.Body.Close()
-	return c.decodeBody(httpResponse, response)
+func (c *RestClient) AuthGetAndDecodeCtx(ctx context.Context, endpoint string, params map[string]interface{}, response interface{}, options ...RequestOption) error {
+	return c.RequestAndDecode(ctx, "GET", endpoint, SecurityTypeUserData, params, response, options...)
 }
 
 func (c *RestClient) decodeBody(r *http.Response, v interface{}) error {
@@ -267,24 +304,7 @@ func (c *RestClient) decodeBody(r *http.Response, v interface{}) error {
 	decoder := json.NewDecoder(bytes.NewReader(raw))
 	decoder.UseNumber()
 	if err := decoder.Decode(v); err != nil {
-		return err
+		return fmt.Errorf("failed to decode body: %v: %s", err, raw)
 	}
 	return nil
 }
-
-type RestApiError struct {
-	StatusCode int
-	Body       []byte
-}
-
-func NewRestApiErrorFromResponse(r *http.Response) *RestApiError {
-	body, _ := ioutil.ReadAll(r.Body)
-	return &RestApiError{
-		StatusCode: r.StatusCode,
-		Body:       body,
-	}
-}
-
-func (e *RestApiError) Error() string {
-	return string(e.Body)
-}
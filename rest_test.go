@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+// TestConcurrentUse shares one client between goroutines that derive
+// clients from it and send requests with both, while the default
+// environment is changed. Run with -race.
+func TestConcurrentUse(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+
+	// Headers of derived clients must not leak into each other or into the
+	// client they were derived from.
+	var mu sync.Mutex
+	var headerErrors []string
+	checkHeaders := func(next binanceapi.RoundTripFunc) binanceapi.RoundTripFunc {
+		return func(call *binanceapi.RestCall) (*http.Response, error) {
+			if workers := call.Request.Header["X-Worker"]; len(workers) > 1 {
+				mu.Lock()
+				headerErrors = append(headerErrors, call.Request.Header.Get("X-Worker"))
+				mu.Unlock()
+			}
+			return next(call)
+		}
+	}
+
+	client := server.Client(
+		binanceapi.WithMiddleware(checkHeaders),
+		binanceapi.WithCache(binanceapi.NewResponseCache(map[string]time.Duration{
+			"/api/v3/ticker/price": time.Millisecond,
+		})))
+
+	const workers, iterations = 8, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*iterations*4)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			derived := client.With(binanceapi.WithHeader("X-Worker", strconv.Itoa(worker)))
+			badAuth := client.WithAuth(server.ApiKey, "wrong secret")
+			for j := 0; j < iterations; j++ {
+				if _, err := client.GetAccount(); err != nil {
+					errs <- err
+				}
+				if _, err := derived.GetPriceTicker("BTCUSDT"); err != nil {
+					errs <- err
+				}
+				if _, err := derived.WithAuth(server.ApiKey, server.Secret).GetAccount(); err != nil {
+					errs <- err
+				}
+				if _, err := badAuth.GetAccount(); err == nil {
+					errs <- errors.New("request signed with a wrong secret succeeded")
+				}
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		env := binanceapi.DefaultEnvironment()
+		defer binanceapi.SetDefaultEnvironment(env)
+		for j := 0; j < 10; j++ {
+			binanceapi.SetDefaultEnvironment(binanceapi.EnvironmentTestnet)
+			binanceapi.NewRestClient()
+			binanceapi.SetDefaultEnvironment(env)
+		}
+	}()
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if len(headerErrors) > 0 {
+		t.Errorf("requests with headers of several clients: %v", headerErrors)
+	}
+}
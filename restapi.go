@@ -23,12 +23,7 @@
 
 package binanceapi
 
-import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-)
+import "context"
 
 // GET /api/v1/time
 type TimeResponse struct {
@@ -36,9 +31,13 @@ type TimeResponse struct {
 }
 
 func (c *RestClient) GetTime() (TimeResponse, error) {
+	return c.GetTimeCtx(context.Background())
+}
+
+func (c *RestClient) GetTimeCtx(ctx context.Context) (TimeResponse, error) {
 	endpoint := "/api/v1/time"
 	var response TimeResponse
-	err := c.GetAndDecode(endpoint, nil, &response)
+	err := c.GetAndDecodeCtx(ctx, endpoint, nil, &response)
 	return response, err
 }
 
@@ -48,19 +47,27 @@ type PriceTickerResponse struct {
 }
 
 func (c *RestClient) GetPriceTicker(symbol string) (PriceTickerResponse, error) {
+	return c.GetPriceTickerCtx(context.Background(), symbol)
+}
+
+func (c *RestClient) GetPriceTickerCtx(ctx context.Context, symbol string) (PriceTickerResponse, error) {
 	endpoint := "/api/v3/ticker/price"
 	var response PriceTickerResponse
 	params := map[string]interface{}{
 		"symbol": symbol,
 	}
-	err := c.GetAndDecode(endpoint, params, &response)
+	err := c.GetAndDecodeCtx(ctx, endpoint, params, &response)
 	return response, err
 }
 
 func (c *RestClient) GetPriceTickerAll() ([]PriceTickerResponse, error) {
+	return c.GetPriceTickerAllCtx(context.Background())
+}
+
+func (c *RestClient) GetPriceTickerAllCtx(ctx context.Context) ([]PriceTickerResponse, error) {
 	endpoint := "/api/v3/ticker/price"
 	var response []PriceTickerResponse
-	err := c.GetAndDecode(endpoint, nil, &response)
+	err := c.GetAndDecodeCtx(ctx, endpoint, nil, &response)
 	return response, err
 }
 
@@ -73,12 +80,16 @@ type BookTickerResponse struct {
 }
 
 func (c *RestClient) GetBookTicker(symbol string) (BookTickerResponse, error) {
+	return c.GetBookTickerCtx(context.Background(), symbol)
+}
+
+func (c *RestClient) GetBookTickerCtx(ctx context.Context, symbol string) (BookTickerResponse, error) {
 	endpoint := "/api/v3/ticker/bookTicker"
 	var response BookTickerResponse
 	params := map[string]interface{}{
 		"symbol": symbol,
 	}
-	err := c.GetAndDecode(endpoint, params, &response)
+	err := c.GetAndDecodeCtx(ctx, endpoint, params, &response)
 	return response, err
 }
 
@@ -87,36 +98,26 @@ type UserDataStreamResponse struct {
 }
 
 func (c *RestClient) GetUserDataStream() (string, error) {
-	httpResponse, err := c.PostWithApiKey("/api/v1/userDataStream", nil)
-	if err != nil {
-		return "", err
-	}
-
-	if httpResponse.StatusCode >= 400 {
-		return "", NewRestApiErrorFromResponse(httpResponse)
-	}
+	return c.GetUserDataStreamCtx(context.Background())
+}
 
+func (c *RestClient) GetUserDataStreamCtx(ctx context.Context) (string, error) {
 	var response UserDataStreamResponse
-	if err := c.decodeBody(httpResponse, &response); err != nil {
-		return "", err
-	}
-
-	return response.ListenKey, nil
+	err := c.RequestAndDecode(ctx, "POST", "/api/v1/userDataStream",
+		SecurityTypeUserStream, nil, &response)
+	return response.ListenKey, err
 }
 
 func (c *RestClient) PutUserStreamKeepAlive(listenKey string) error {
-	queryString := c.BuildQueryString(map[string]interface{}{
+	return c.PutUserStreamKeepAliveCtx(context.Background(), listenKey)
+}
+
+func (c *RestClient) PutUserStreamKeepAliveCtx(ctx context.Context, listenKey string) error {
+	params := map[string]interface{}{
 		"listenKey": listenKey,
-	})
-	path := fmt.Sprintf("/api/v1/userDataStream?%s", queryString)
-	httpResponse, err := c.PutWithApiKey(path)
-	if err != nil {
-		return err
 	}
-	if httpResponse.StatusCode != http.StatusOK {
-		return NewRestApiErrorFromResponse(httpResponse)
-	}
-	return nil
+	return c.RequestAndDecode(ctx, "PUT", "/api/v1/userDataStream",
+		SecurityTypeUserStream, params, nil)
 }
 
 type QueryOrderResponse struct {
@@ -125,7 +126,7 @@ type QueryOrderResponse struct {
 	ClientOrderId string      `json:"clientOrderId"`
 	Price         float64     `json:"price,string"`
 	OrigQty       float64     `json:"origQty,string"`
-	ExecutedQty   float64     `json:"executeQty,string"`
+	ExecutedQty   float64     `json:"executedQty,string"`
 	Status        OrderStatus `json:"status"`
 	TimeInForce   TimeInForce `json:"timeInForce"`
 	Type          OrderType   `json:"type"`
@@ -136,46 +137,32 @@ type QueryOrderResponse struct {
 	IsWorking     bool        `json:"isWorking"`
 }
 
-func (c *RestClient) GetOrderByOrderId(symbol string, orderId int64) (QueryOrderResponse, error) {
+func (c *RestClient) GetOrderByOrderId(symbol string, orderId int64, options ...RequestOption) (QueryOrderResponse, error) {
+	return c.GetOrderByOrderIdCtx(context.Background(), symbol, orderId, options...)
+}
+
+func (c *RestClient) GetOrderByOrderIdCtx(ctx context.Context, symbol string, orderId int64, options ...RequestOption) (QueryOrderResponse, error) {
 	var response QueryOrderResponse
 	params := map[string]interface{}{
 		"symbol":  symbol,
 		"orderId": orderId,
 	}
-	httpResponse, err := c.GetWithAuth("/api/v3/order", params)
-	if err != nil {
-		return response, err
-	}
-	defer httpResponse.Body.Close()
-	if httpResponse.StatusCode != http.StatusOK {
-		return response, NewRestApiErrorFromResponse(httpResponse)
-	}
-	decoder := json.NewDecoder(httpResponse.Body)
-	if err := decoder.Decode(&response); err != nil {
-		return response, err
-	}
-	return response, nil
+	err := c.RequestAndDecode(ctx, "GET", "/api/v3/order", SecurityTypeUserData, params, &response, options...)
+	return response, err
+}
+
+func (c *RestClient) GetOrderByClientId(symbol string, clientId string, options ...RequestOption) (QueryOrderResponse, error) {
+	return c.GetOrderByClientIdCtx(context.Background(), symbol, clientId, options...)
 }
 
-func (c *RestClient) GetOrderByClientId(symbol string, clientId string) (QueryOrderResponse, error) {
+func (c *RestClient) GetOrderByClientIdCtx(ctx context.Context, symbol string, clientId string, options ...RequestOption) (QueryOrderResponse, error) {
 	var response QueryOrderResponse
 	params := map[string]interface{}{
 		"symbol":            symbol,
 		"origClientOrderId": clientId,
 	}
-	httpResponse, err := c.GetWithAuth("/api/v3/order", params)
-	if err != nil {
-		return response, err
-	}
-	defer httpResponse.Body.Close()
-	if httpResponse.StatusCode != http.StatusOK {
-		return response, NewRestApiErrorFromResponse(httpResponse)
-	}
-	decoder := json.NewDecoder(httpResponse.Body)
-	if err := decoder.Decode(&response); err != nil {
-		return response, err
-	}
-	return response, nil
+	err := c.RequestAndDecode(ctx, "GET", "/api/v3/order", SecurityTypeUserData, params, &response, options...)
+	return response, err
 }
 
 type MyTradesResponseEntry struct {
@@ -191,7 +178,11 @@ type MyTradesResponseEntry struct {
 	IsBestMatch     bool    `json:"isBestMatch"`
 }
 
-func (c *RestClient) GetMytrades(symbol string, limit int64, fromId int64) ([]MyTradesResponseEntry, error) {
+func (c *RestClient) GetMytrades(symbol string, limit int64, fromId int64, options ...RequestOption) ([]MyTradesResponseEntry, error) {
+	return c.GetMytradesCtx(context.Background(), symbol, limit, fromId, options...)
+}
+
+func (c *RestClient) GetMytradesCtx(ctx context.Context, symbol string, limit int64, fromId int64, options ...RequestOption) ([]MyTradesResponseEntry, error) {
 	endpoint := "/api/v3/myTrades"
 	params := map[string]interface{}{
 		"symbol": symbol,
@@ -203,22 +194,7 @@ func (c *RestClient) GetMytrades(symbol string, limit int64, fromId int64) ([]My
 		params["fromId"] = fromId
 	}
 	var response []MyTradesResponseEntry
-	httpResponse, err := c.GetWithAuth(endpoint, params)
-	if err != nil {
-		return response, err
-	}
-	if httpResponse.StatusCode != http.StatusOK {
-		return response, fmt.Errorf("%s", httpResponse.Status)
-	}
-	body, err := ioutil.ReadAll(httpResponse.Body)
-	if err != nil {
-		return response, err
-	}
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return response, fmt.Errorf("failed to decode body: %v: %s",
-			err, body)
-	}
+	err := c.RequestAndDecode(ctx, "GET", endpoint, SecurityTypeUserData, params, &response, options...)
 	return response, err
 }
 
@@ -240,24 +216,14 @@ type AccountInfoResponse struct {
 	Balances         []AccountInfoBalance `json:"balances"`
 }
 
-func (c *RestClient) GetAccount() (*AccountInfoResponse, error) {
-	httpResponse, err := c.GetWithAuth("/api/v3/account", nil)
-	if err != nil {
-		return nil, err
-	}
-	if httpResponse.StatusCode >= 400 {
-		return nil, NewRestApiErrorFromResponse(httpResponse)
-	}
-
-	body, err := ioutil.ReadAll(httpResponse.Body)
-	if err != nil {
-		return nil, err
-	}
+func (c *RestClient) GetAccount(options ...RequestOption) (*AccountInfoResponse, error) {
+	return c.GetAccountCtx(context.Background(), options...)
+}
 
+func (c *RestClient) GetAccountCtx(ctx context.Context, options ...RequestOption) (*AccountInfoResponse, error) {
 	var response AccountInfoResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := c.RequestAndDecode(ctx, "GET", "/api/v3/account", SecurityTypeUserData, nil, &response, options...); err != nil {
 		return nil, err
 	}
-
 	return &response, nil
 }
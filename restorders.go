@@ -24,10 +24,8 @@
 package binanceapi
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 )
 
 type OrderSide string
@@ -61,6 +59,14 @@ const (
 	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
 )
 
+type OrderRespType string
+
+const (
+	OrderRespTypeAck    OrderRespType = "ACK"
+	OrderRespTypeResult OrderRespType = "RESULT"
+	OrderRespTypeFull   OrderRespType = "FULL"
+)
+
 type OrderParameters struct {
 	Symbol           string
 	Side             OrderSide
@@ -69,17 +75,44 @@ type OrderParameters struct {
 	Quantity         float64
 	Price            float64
 	NewClientOrderId string
+
+	// Defaults to FULL for LIMIT and MARKET orders, ACK otherwise.
+	NewOrderRespType OrderRespType
+}
+
+type PostOrderFill struct {
+	Price           float64 `json:"price,string"`
+	Quantity        float64 `json:"qty,string"`
+	Commission      float64 `json:"commission,string"`
+	CommissionAsset string  `json:"commissionAsset"`
+	TradeId         int64   `json:"tradeId"`
 }
 
-// TODO: Implement RESULT and FULL response types. Currently only ACK implemented.
+// PostOrderResponse is the response to a new order. The fields after
+// TransactionTimeMillis are only set for the RESULT and FULL response types,
+// and Fills only for FULL.
 type PostOrderResponse struct {
 	Symbol                string `json:"symbol"`
 	OrderId               int64  `json:"orderId"`
 	ClientOrderId         string `json:"clientOrderId"`
 	TransactionTimeMillis int64  `json:"transactTime"`
+
+	Price               float64         `json:"price,string"`
+	OrigQty             float64         `json:"origQty,string"`
+	ExecutedQty         float64         `json:"executedQty,string"`
+	CummulativeQuoteQty float64         `json:"cummulativeQuoteQty,string"`
+	Status              OrderStatus     `json:"status"`
+	TimeInForce         TimeInForce     `json:"timeInForce"`
+	Type                OrderType       `json:"type"`
+	Side                OrderSide       `json:"side"`
+	Fills               []PostOrderFill `json:"fills"`
+}
+
+func (c *RestClient) PostOrder(order OrderParameters, options ...RequestOption) (PostOrderResponse, error) {
+	return c.PostOrderCtx(context.Background(), order, options...)
 }
 
-func (c *RestClient) PostOrder(order OrderParameters) (*http.Response, error) {
+func (c *RestClient) PostOrderCtx(ctx context.Context, order OrderParameters, options ...RequestOption) (PostOrderResponse, error) {
 	params := map[string]interface{}{}
 	params["symbol"] = order.Symbol
 	params["side"] = order.Side
@@ -95,15 +128,13 @@ func (c *RestClient) PostOrder(order OrderParameters) (*http.Response, error) {
 	if order.TimeInForce != "" {
 		params["timeInForce"] = order.TimeInForce
 	}
-
-	response, err := c.Post("/api/v3/order", params)
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode >= 400 {
-		return response, NewRestApiErrorFromResponse(response)
+	if order.NewOrderRespType != "" {
+		params["newOrderRespType"] = order.NewOrderRespType
 	}
-	return response, nil
+
+	var response PostOrderResponse
+	err := c.RequestAndDecode(ctx, "POST", "/api/v3/order", SecurityTypeTrade, params, &response, options...)
+	return response, err
 }
 
 type CancelOrderResponse struct {
@@ -113,28 +144,15 @@ type CancelOrderResponse struct {
 	ClientOrderID     string `json:"clientOrderId"`
 }
 
-func (c *RestClient) CancelOrderById(symbol string, orderId int64) (CancelOrderResponse, error) {
+func (c *RestClient) CancelOrderById(symbol string, orderId int64, options ...RequestOption) (CancelOrderResponse, error) {
+	return c.CancelOrderByIdCtx(context.Background(), symbol, orderId, options...)
+}
+
+func (c *RestClient) CancelOrderByIdCtx(ctx context.Context, symbol string, orderId int64, options ...RequestOption) (CancelOrderResponse, error) {
 	var cancelOrderResponse CancelOrderResponse
 	params := map[string]interface{}{}
 	params["symbol"] = symbol
 	params["orderId"] = orderId
-
-	httpResponse, err := c.Delete("/api/v3/order", params)
-	if err != nil {
-		return cancelOrderResponse, err
-	}
-
-	if httpResponse.StatusCode != http.StatusOK {
-		return cancelOrderResponse, NewRestApiErrorFromResponse(httpResponse)
-	}
-
-	body, err := ioutil.ReadAll(httpResponse.Body)
-	if err != nil {
-		return cancelOrderResponse, err
-	}
-
-	if err := json.Unmarshal(body, &cancelOrderResponse); err != nil {
-		return cancelOrderResponse, err
-	}
-	return cancelOrderResponse, nil
+	err := c.RequestAndDecode(ctx, "DELETE", "/api/v3/order", SecurityTypeTrade, params, &cancelOrderResponse, options...)
+	return cancelOrderResponse, err
 }
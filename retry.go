@@ -0,0 +1,186 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy decides whether a failed request should be sent again. It is
+// only consulted for idempotent requests, ie. market data and account
+// queries, never for requests that place or cancel orders as a failure does
+// not tell if the server acted on them.
+type RetryPolicy interface {
+	// Retry is called after attempt number attempt, starting at 1, failed
+	// with either err or an error response. It returns how long to wait
+	// before the next attempt, or false to give up.
+	Retry(attempt int, response *http.Response, err error) (time.Duration, bool)
+}
+
+// WithRetryPolicy sets the policy used to retry failed idempotent requests.
+// By default requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) RestClientOption {
+	return func(c *RestClient) {
+		c.retryPolicy = policy
+	}
+}
+
+// ExponentialBackoff retries transient network errors and 5xx responses with
+// an exponentially increasing delay with jitter. A 429 or 418 response is
+// retried after the server provided Retry-After, but only if that is no
+// longer than MaxDelay. A 429 without Retry-After is retried with the same
+// backoff as other failures, a 418 without it is not retried.
+type ExponentialBackoff struct {
+	// Number of retries after the first attempt.
+	MaxRetries int
+
+	// Delay before the first retry, doubled for each following retry.
+	BaseDelay time.Duration
+
+	// Upper bound on the delay between attempts.
+	MaxDelay time.Duration
+}
+
+// NewExponentialBackoff returns an ExponentialBackoff with defaults suitable
+// for market data requests.
+func NewExponentialBackoff() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxRetries: 3,
+		BaseDelay:  250 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+func (p *ExponentialBackoff) Retry(attempt int, response *http.Response, err error) (time.Duration, bool) {
+	if attempt > p.MaxRetries {
+		return 0, false
+	}
+
+	if err != nil {
+		if !isTransientError(err) {
+			return 0, false
+		}
+		return p.backoff(attempt), true
+	}
+
+	switch {
+	case response.StatusCode == http.StatusTooManyRequests,
+		response.StatusCode == http.StatusTeapot:
+		// On 418 Retry-After is the length of the IP ban, which is
+		// usually far too long to wait.
+		retryAfter, err := strconv.ParseInt(response.Header.Get("Retry-After"), 10, 64)
+		if err != nil {
+			if response.StatusCode == http.StatusTeapot {
+				return 0, false
+			}
+			return p.backoff(attempt), true
+		}
+		delay := time.Duration(retryAfter) * time.Second
+		if delay > p.MaxDelay {
+			return 0, false
+		}
+		return delay, true
+	case response.StatusCode >= 500:
+		return p.backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// backoff returns a delay of between half and all of BaseDelay*2^(attempt-1)
+// capped at MaxDelay.
+func (p *ExponentialBackoff) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << uint(attempt-1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// isTransientError returns true for network errors that may succeed if the
+// request is sent again.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// retry calls send until it succeeds or the retry policy gives up. Only GET
+// requests are retried.
+func (c *RestClient) retry(ctx context.Context, method string, send func() (*http.Response, error)) (*http.Response, error) {
+	if c.retryPolicy == nil || method != "GET" {
+		return send()
+	}
+
+	for attempt := 1; ; attempt++ {
+		response, err := send()
+		if err == nil && response.StatusCode < 400 {
+			return response, nil
+		}
+
+		// Errors raised before the request was sent, such as a local
+		// rate limit, are returned as is.
+		var rateLimitErr *RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			return nil, err
+		}
+
+		delay, ok := c.retryPolicy.Retry(attempt, response, err)
+		if !ok {
+			return response, err
+		}
+		if response != nil {
+			closeResponse(response)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
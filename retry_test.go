@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+func newRetryClient(server *binanceapitest.Server) *binanceapi.RestClient {
+	return server.Client(binanceapi.WithRetryPolicy(&binanceapi.ExponentialBackoff{
+		MaxRetries: 3,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Second,
+	}))
+}
+
+func TestRetryServerError(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	server.Script("GET", "/api/v3/ticker/price", binanceapitest.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       "Service Unavailable",
+	})
+
+	ticker, err := newRetryClient(server).GetPriceTicker("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticker.Price != 50000 {
+		t.Errorf("unexpected price %v", ticker.Price)
+	}
+	if n := countRequests(server, "/api/v3/ticker/price"); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestRetryNotForOrders(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	server.Script("POST", "/api/v3/order", binanceapitest.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       "Service Unavailable",
+	})
+
+	_, err := newRetryClient(server).PostOrder(binanceapi.OrderParameters{
+		Symbol:   "BTCUSDT",
+		Side:     binanceapi.OrderSideBuy,
+		Type:     binanceapi.OrderTypeMarket,
+		Quantity: 0.001,
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := countRequests(server, "/api/v3/order"); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
+
+func TestRetryAfterTooManyRequests(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	response := binanceapitest.ErrorResponse(http.StatusTooManyRequests, -1003, "Too many requests.")
+	response.Header = http.Header{"Retry-After": []string{"1"}}
+	server.Script("GET", "/api/v3/ticker/price", response)
+
+	start := time.Now()
+	if _, err := newRetryClient(server).GetPriceTicker("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, before Retry-After", elapsed)
+	}
+	if n := countRequests(server, "/api/v3/ticker/price"); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestBanBlocksSharedClients(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	response := binanceapitest.ErrorResponse(http.StatusTeapot, -1003, "Way too many requests; IP banned.")
+	response.Header = http.Header{"Retry-After": []string{"120"}}
+	server.Script("GET", "/api/v3/ticker/price", response)
+
+	client := newRetryClient(server)
+	if _, err := client.GetPriceTicker("BTCUSDT"); !binanceapi.IsIPBanned(err) {
+		t.Fatalf("expected an IP ban, got %v", err)
+	}
+
+	// Neither other endpoints nor derived clients may send during the ban.
+	derived := client.WithAuth(server.ApiKey, server.Secret)
+	for _, send := range []func() error{
+		func() error { _, err := client.GetBookTicker("BTCUSDT"); return err },
+		func() error { _, err := derived.GetAccount(); return err },
+		func() error { _, err := derived.GetPriceTicker("ETHUSDT"); return err },
+	} {
+		var rateLimitErr *binanceapi.RateLimitError
+		if err := send(); !errors.As(err, &rateLimitErr) {
+			t.Errorf("expected *RateLimitError, got %v", err)
+		} else if rateLimitErr.Wait < 110*time.Second {
+			t.Errorf("unexpected wait %v", rateLimitErr.Wait)
+		}
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
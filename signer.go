@@ -0,0 +1,231 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+)
+
+// Signer signs the payload of a signed request. The returned signature is
+// sent as the signature parameter.
+type Signer interface {
+	Sign(payload []byte) (string, error)
+}
+
+// SignedParams is a parameter set encoded and signed exactly as RestClient
+// sends it.
+type SignedParams struct {
+	// The parameters encoded as a query string sorted by key, which is the
+	// payload that was signed.
+	QueryString string
+
+	Signature string
+}
+
+// Encode returns the query string with the signature parameter appended,
+// ready to be sent as the query string or form body of a signed request.
+func (p SignedParams) Encode() string {
+	return appendSignature(p.QueryString, p.Signature)
+}
+
+// SignParams encodes params and signs them with signer. Binance also
+// requires signed requests to carry a timestamp parameter in milliseconds,
+// which must be included in params.
+func SignParams(signer Signer, params map[string]interface{}) (SignedParams, error) {
+	queryString := encodeParams(params)
+	signature, err := sign(signer, []byte(queryString))
+	if err != nil {
+		return SignedParams{}, err
+	}
+	return SignedParams{
+		QueryString: queryString,
+		Signature:   signature,
+	}, nil
+}
+
+func sign(signer Signer, payload []byte) (string, error) {
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign request: %v", err)
+	}
+	return signature, nil
+}
+
+// appendSignature adds the signature parameter to the encoded parameters.
+func appendSignature(encoded string, signature string) string {
+	signatureParam := "signature=" + url.QueryEscape(signature)
+	if encoded == "" {
+		return signatureParam
+	}
+	return encoded + "&" + signatureParam
+}
+
+// HmacSigner signs requests with the secret of an HMAC API key.
+type HmacSigner struct {
+	secret []byte
+}
+
+func NewHmacSigner(secret string) *HmacSigner {
+	return &HmacSigner{
+		secret: []byte(secret),
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of payload.
+func (s *HmacSigner) Sign(payload []byte) (string, error) {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (s *HmacSigner) String() string {
+	return "HmacSigner{" + redacted + "}"
+}
+
+func (s *HmacSigner) GoString() string {
+	return s.String()
+}
+
+// RsaSigner signs requests with the private key of an RSA API key.
+type RsaSigner struct {
+	key *rsa.PrivateKey
+}
+
+func NewRsaSigner(key *rsa.PrivateKey) *RsaSigner {
+	return &RsaSigner{
+		key: key,
+	}
+}
+
+// NewRsaSignerFromPem creates an RsaSigner from a PEM encoded PKCS#8 or
+// PKCS#1 private key.
+func NewRsaSignerFromPem(data []byte) (*RsaSigner, error) {
+	key, err := parsePemPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA private key: %T", key)
+	}
+	return NewRsaSigner(rsaKey), nil
+}
+
+// Sign returns the base64 encoded RSASSA-PKCS1-v1_5 SHA-256 signature of
+// payload.
+func (s *RsaSigner) Sign(payload []byte) (string, error) {
+	hashed := sha256.Sum256(payload)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func (s *RsaSigner) String() string {
+	return "RsaSigner{" + redacted + "}"
+}
+
+func (s *RsaSigner) GoString() string {
+	return s.String()
+}
+
+// Ed25519Signer signs requests with the private key of an Ed25519 API key.
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+func NewEd25519Signer(key ed25519.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{
+		key: key,
+	}
+}
+
+// NewEd25519SignerFromPem creates an Ed25519Signer from a PEM encoded PKCS#8
+// private key.
+func NewEd25519SignerFromPem(data []byte) (*Ed25519Signer, error) {
+	key, err := parsePemPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an Ed25519 private key: %T", key)
+	}
+	return NewEd25519Signer(edKey), nil
+}
+
+// Sign returns the base64 encoded Ed25519 signature of payload.
+func (s *Ed25519Signer) Sign(payload []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload)), nil
+}
+
+func (s *Ed25519Signer) String() string {
+	return "Ed25519Signer{" + redacted + "}"
+}
+
+func (s *Ed25519Signer) GoString() string {
+	return s.String()
+}
+
+// NewSignerFromPem creates an RsaSigner or Ed25519Signer depending on the
+// type of the PEM encoded private key.
+func NewSignerFromPem(data []byte) (Signer, error) {
+	key, err := parsePemPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return NewRsaSigner(key), nil
+	case ed25519.PrivateKey:
+		return NewEd25519Signer(key), nil
+	}
+	return nil, fmt.Errorf("unsupported private key type: %T", key)
+}
+
+func parsePemPrivateKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+}
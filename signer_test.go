@@ -0,0 +1,228 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+// The example from the Binance API documentation for signed endpoints.
+const (
+	docsSecret    = "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+	docsPayload   = "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559"
+	docsSignature = "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71"
+)
+
+func TestHmacSigner(t *testing.T) {
+	signature, err := binanceapi.NewHmacSigner(docsSecret).Sign([]byte(docsPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature != docsSignature {
+		t.Errorf("expected %s, got %s", docsSignature, signature)
+	}
+}
+
+func TestRsaSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := binanceapi.NewSignerFromPem(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := signer.(*binanceapi.RsaSigner); !ok {
+		t.Fatalf("expected *RsaSigner, got %T", signer)
+	}
+
+	signature := decodeSignature(t, signer)
+	hashed := sha256.Sum256([]byte(docsPayload))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEd25519Signer(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := binanceapi.NewSignerFromPem(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := signer.(*binanceapi.Ed25519Signer); !ok {
+		t.Fatalf("expected *Ed25519Signer, got %T", signer)
+	}
+
+	if !ed25519.Verify(public, []byte(docsPayload), decodeSignature(t, signer)) {
+		t.Error("signature does not verify")
+	}
+}
+
+// SignParams gives the same encoding and signature as a request sent by the
+// client, with the parameters in either the query string or the body.
+func TestSignParamsMatchesRequests(t *testing.T) {
+	var method, sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := ioutil.ReadAll(r.Body)
+		method, sent = r.Method, r.URL.RawQuery
+		if len(buf) > 0 {
+			sent = string(buf)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := []binanceapi.Signer{binanceapi.NewHmacSigner(docsSecret), binanceapi.NewEd25519Signer(private)}
+	for _, signer := range signers {
+		for _, paramsInBody := range []bool{false, true} {
+			client := binanceapi.NewRestClient(
+				binanceapi.WithBaseUrl(server.URL),
+				binanceapi.WithSigner("key", signer),
+				binanceapi.WithTimeSync(0))
+			params := map[string]interface{}{
+				"symbol":   "LTCBTC",
+				"side":     "BUY",
+				"quantity": 1,
+				"price":    0.1,
+				"note":     "a b+c/d",
+			}
+			options := []binanceapi.RequestOption{}
+			if paramsInBody {
+				options = append(options, binanceapi.ParamsInBody())
+			}
+			if _, err := client.Post("/api/v3/order", params, options...); err != nil {
+				t.Fatal(err)
+			}
+			if method != "POST" {
+				t.Fatalf("unexpected method %s", method)
+			}
+
+			values, err := url.ParseQuery(sent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			timestamp, err := strconv.ParseInt(values.Get("timestamp"), 10, 64)
+			if err != nil {
+				t.Fatalf("no timestamp in %q", sent)
+			}
+			params["timestamp"] = timestamp
+			params["recvWindow"] = values.Get("recvWindow")
+			signed, err := binanceapi.SignParams(signer, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signed.Encode() != sent {
+				t.Errorf("%T, params in body %v: SignParams gave %q, client sent %q",
+					signer, paramsInBody, signed.Encode(), sent)
+			}
+		}
+	}
+}
+
+func TestServerVerifiesKeyTypes(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		publicKey crypto.PublicKey
+		signer    binanceapi.Signer
+		valid     bool
+	}{
+		{nil, binanceapi.NewHmacSigner(binanceapitest.DefaultSecret), true},
+		{nil, binanceapi.NewHmacSigner("wrong secret"), false},
+		{&rsaKey.PublicKey, binanceapi.NewRsaSigner(rsaKey), true},
+		{&rsaKey.PublicKey, binanceapi.NewHmacSigner(binanceapitest.DefaultSecret), false},
+		{edPublic, binanceapi.NewEd25519Signer(edPrivate), true},
+		{edPublic, binanceapi.NewEd25519Signer(otherPrivate), false},
+		{edPublic, binanceapi.NewRsaSigner(rsaKey), false},
+	}
+	for _, test := range tests {
+		server := binanceapitest.NewServer()
+		server.PublicKey = test.publicKey
+		client := server.Client(binanceapi.WithSigner(server.ApiKey, test.signer))
+		_, err := client.GetAccount()
+		server.Close()
+		if test.valid && err != nil {
+			t.Errorf("%T key, %T: %v", test.publicKey, test.signer, err)
+		}
+		if !test.valid && !binanceapi.IsInvalidSignature(err) {
+			t.Errorf("%T key, %T: expected an invalid signature error, got %v",
+				test.publicKey, test.signer, err)
+		}
+	}
+}
+
+func decodeSignature(t *testing.T, signer binanceapi.Signer) []byte {
+	t.Helper()
+	signature, err := signer.Sign([]byte(docsPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		t.Fatalf("signature is not base64: %v", err)
+	}
+	return decoded
+}
@@ -29,10 +29,14 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"strings"
 )
 
-var STREAM_URL = "wss://stream.binance.com:9443"
+// The root URL of the market data streams opened by the package level
+// stream openers.
+//
+// Deprecated: Writes to STREAM_URL are not synchronised with the stream
+// openers. Use SetDefaultEnvironment, or open streams in an Environment.
+var STREAM_URL = defaultEnvironment.StreamUrl
 
 type StreamType int
 
@@ -48,6 +52,7 @@ func init() {
 	if envStreamUrl != "" {
 		log.Printf("Using Binance Stream URL from environment: %s", envStreamUrl)
 		STREAM_URL = envStreamUrl
+		defaultEnvironment.StreamUrl = envStreamUrl
 	}
 }
 
@@ -57,21 +62,20 @@ type Stream struct {
 }
 
 func OpenStream(stream string) (*Stream, error) {
-	url := fmt.Sprintf("%s/%s", STREAM_URL, stream)
-	conn, response, err := websocket.DefaultDialer.Dial(url, nil)
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode != http.StatusSwitchingProtocols {
-		return nil, fmt.Errorf("failed to upgrade to websocket: %s", response.Status)
-	}
-	return &Stream{
-		Conn: conn,
-	}, nil
+	return defaultStreamEnvironment().OpenStream(stream)
 }
 
 func OpenSingleStream(stream string) (*Stream, error) {
-	url := fmt.Sprintf("%s/ws/%s", STREAM_URL, stream)
+	return defaultStreamEnvironment().OpenSingleStream(stream)
+}
+
+// OpenUserDataStream opens the user data stream for a listen key obtained
+// with GetUserDataStream in the default environment.
+func OpenUserDataStream(listenKey string) (*Stream, error) {
+	return DefaultEnvironment().OpenUserDataStream(listenKey)
+}
+
+func openStreamUrl(url string) (*Stream, error) {
 	conn, response, err := websocket.DefaultDialer.Dial(url, nil)
 	if err != nil {
 		return nil, err
@@ -97,19 +101,9 @@ func (s *Stream) Next() ([]byte, error) {
 }
 
 func OpenPartialBookDepthStream(symbol string, depth int) (*Stream, error) {
-	stream, err := OpenSingleStream(fmt.Sprintf("%s@depth%d", strings.ToLower(symbol), depth))
-	if err != nil {
-		return nil, err
-	}
-	stream.Type = STREAM_TYPE_PARTIAL_BOOK
-	return stream, nil
+	return defaultStreamEnvironment().OpenPartialBookDepthStream(symbol, depth)
 }
 
 func OpenAllMarketTickerStream() (*Stream, error) {
-	stream, err := OpenSingleStream("!ticker@arr")
-	if err != nil {
-		return nil, err
-	}
-	stream.Type = STREAM_TYPE_PARTIAL_BOOK
-	return stream, nil
+	return defaultStreamEnvironment().OpenAllMarketTickerStream()
 }
@@ -28,37 +28,79 @@ import (
 	"time"
 )
 
-// Stream name: <symbol>@ticker.
+// Stream name: <symbol>@ticker. The statistics are the same as returned by
+// GetTicker24hr, but are encoded with the short keys of the stream.
 type TickerStreamMessage struct {
-	EventType            string  `json:"e"`
-	EventTime            int64   `json:"E"`
-	Symbol               string  `json:"s"`
-	PriceChange          float64 `json:"p,string"`
-	PriceChangePercent   float64 `json:"P,string"`
-	WeightedAveragePrice float64 `json:"w,string"`
-	PreviousDayClose     float64 `json:"x,string"`
-	CurrentDayClose      float64 `json:"c,string"`
-	CloseTradeQuantity   float64 `json:"Q,string"`
-	Bid                  float64 `json:"b,string"`
-	BidQuantity          float64 `json:"B,string"`
-	Ask                  float64 `json:"a,string"`
-	AskQuantity          float64 `json:"A,string"`
-	OpenPrice            float64 `json:"o,string"`
-	HighPrice            float64 `json:"h,string"`
-	LowPrice             float64 `json:"l,string"`
-	TotalBaseVolume      float64 `json:"v,string"`
-	TotalQuoteVolume     float64 `json:"q,string"`
-	StatsOpenTime        int64   `json:"O"`
-	StatsCloseTime       int64   `json:"C"`
-	FirstTradeID         int64   `json:"F"`
-	LastTradeID          int64   `json:"L"`
-	TotalNumberTrades    int64   `json:"n"`
+	EventType string
+	EventTime int64
+	TickerStatistics
 }
 
 func (t *TickerStreamMessage) Timestamp() time.Time {
 	return time.Unix(0, t.EventTime*int64(time.Millisecond))
 }
 
+// streamFields returns the fields of t tagged with the keys of the stream.
+func (t *TickerStreamMessage) streamFields() interface{} {
+	return &struct {
+		EventType            *string  `json:"e"`
+		EventTime            *int64   `json:"E"`
+		Symbol               *string  `json:"s"`
+		PriceChange          *float64 `json:"p,string"`
+		PriceChangePercent   *float64 `json:"P,string"`
+		WeightedAveragePrice *float64 `json:"w,string"`
+		PreviousDayClose     *float64 `json:"x,string"`
+		CurrentDayClose      *float64 `json:"c,string"`
+		CloseTradeQuantity   *float64 `json:"Q,string"`
+		Bid                  *float64 `json:"b,string"`
+		BidQuantity          *float64 `json:"B,string"`
+		Ask                  *float64 `json:"a,string"`
+		AskQuantity          *float64 `json:"A,string"`
+		OpenPrice            *float64 `json:"o,string"`
+		HighPrice            *float64 `json:"h,string"`
+		LowPrice             *float64 `json:"l,string"`
+		TotalBaseVolume      *float64 `json:"v,string"`
+		TotalQuoteVolume     *float64 `json:"q,string"`
+		StatsOpenTime        *int64   `json:"O"`
+		StatsCloseTime       *int64   `json:"C"`
+		FirstTradeID         *int64   `json:"F"`
+		LastTradeID          *int64   `json:"L"`
+		TotalNumberTrades    *int64   `json:"n"`
+	}{
+		&t.EventType,
+		&t.EventTime,
+		&t.Symbol,
+		&t.PriceChange,
+		&t.PriceChangePercent,
+		&t.WeightedAveragePrice,
+		&t.PreviousDayClose,
+		&t.CurrentDayClose,
+		&t.CloseTradeQuantity,
+		&t.Bid,
+		&t.BidQuantity,
+		&t.Ask,
+		&t.AskQuantity,
+		&t.OpenPrice,
+		&t.HighPrice,
+		&t.LowPrice,
+		&t.TotalBaseVolume,
+		&t.TotalQuoteVolume,
+		&t.StatsOpenTime,
+		&t.StatsCloseTime,
+		&t.FirstTradeID,
+		&t.LastTradeID,
+		&t.TotalNumberTrades,
+	}
+}
+
+func (t *TickerStreamMessage) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, t.streamFields())
+}
+
+func (t TickerStreamMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.streamFields())
+}
+
 func DecodeAllMarketTickerStream(payload []byte) ([]TickerStreamMessage, error) {
 	var message []TickerStreamMessage
 	if err := json.Unmarshal(payload, &message); err != nil {
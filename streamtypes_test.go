@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+)
+
+// The example from the Binance documentation of the ticker stream.
+const tickerStreamPayload = `{"e":"24hrTicker","E":123456789,"s":"BNBBTC",` +
+	`"p":"0.0015","P":"250.00","w":"0.0018","x":"0.0009","c":"0.0025",` +
+	`"Q":"10","b":"0.0024","B":"10","a":"0.0026","A":"100","o":"0.0010",` +
+	`"h":"0.0025","l":"0.0010","v":"10000","q":"18","O":0,"C":86400000,` +
+	`"F":0,"L":18150,"n":18151}`
+
+func TestDecodeTickerStream(t *testing.T) {
+	expected := binanceapi.TickerStreamMessage{
+		EventType: "24hrTicker",
+		EventTime: 123456789,
+		TickerStatistics: binanceapi.TickerStatistics{
+			Symbol:               "BNBBTC",
+			PriceChange:          0.0015,
+			PriceChangePercent:   250,
+			WeightedAveragePrice: 0.0018,
+			PreviousDayClose:     0.0009,
+			CurrentDayClose:      0.0025,
+			CloseTradeQuantity:   10,
+			Bid:                  0.0024,
+			BidQuantity:          10,
+			Ask:                  0.0026,
+			AskQuantity:          100,
+			OpenPrice:            0.0010,
+			HighPrice:            0.0025,
+			LowPrice:             0.0010,
+			TotalBaseVolume:      10000,
+			TotalQuoteVolume:     18,
+			StatsOpenTime:        0,
+			StatsCloseTime:       86400000,
+			FirstTradeID:         0,
+			LastTradeID:          18150,
+			TotalNumberTrades:    18151,
+		},
+	}
+
+	tickers, err := binanceapi.DecodeAllMarketTickerStream([]byte("[" + tickerStreamPayload + "]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickers) != 1 || !reflect.DeepEqual(tickers[0], expected) {
+		t.Fatalf("unexpected tickers %+v", tickers)
+	}
+
+	message, err := binanceapi.DecodeCombinedStreamMessage(
+		[]byte(`{"stream":"!ticker@arr","data":[` + tickerStreamPayload + `]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(message.Tickers) != 1 || !reflect.DeepEqual(message.Tickers[0], expected) {
+		t.Errorf("unexpected combined stream tickers %+v", message.Tickers)
+	}
+
+	// Encoding uses the keys of the stream, not of the REST API.
+	buf, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded binanceapi.TickerStreamMessage
+	if err := json.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("%s decoded to %+v", buf, decoded)
+	}
+}
@@ -0,0 +1,120 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// timeSync tracks the offset between the local clock and the Binance server
+// clock so signed requests carry a timestamp the server will accept.
+type timeSync struct {
+	mu       sync.Mutex
+	offset   time.Duration
+	lastSync time.Time
+	syncing  bool
+}
+
+// How often the offset to the server clock is refreshed by default.
+const DefaultTimeSyncInterval = time.Minute
+
+// WithTimeSync refreshes the offset to the server clock every interval,
+// DefaultTimeSyncInterval by default. The offset is measured before the
+// first signed request and refreshed lazily before a signed request once the
+// interval has passed. 0 only syncs when Binance rejects a timestamp.
+func WithTimeSync(interval time.Duration) RestClientOption {
+	return func(c *RestClient) {
+		c.timeSyncInterval = interval
+	}
+}
+
+// SyncTime measures the offset between the local clock and the server clock
+// and applies it to the timestamp of all following signed requests. Half of
+// the round trip time is assumed to have passed when the server read its
+// clock.
+func (c *RestClient) SyncTime(ctx context.Context) error {
+	start := time.Now()
+	response, err := c.GetTimeCtx(ctx)
+	if err != nil {
+		return err
+	}
+	end := time.Now()
+
+	local := start.Add(end.Sub(start) / 2)
+	server := time.Unix(0, response.ServerTime*int64(time.Millisecond))
+
+	c.timeSync.mu.Lock()
+	c.timeSync.offset = server.Sub(local)
+	c.timeSync.lastSync = end
+	c.timeSync.mu.Unlock()
+	return nil
+}
+
+// TimeOffset returns the current offset of the server clock from the local
+// clock.
+func (c *RestClient) TimeOffset() time.Duration {
+	c.timeSync.mu.Lock()
+	defer c.timeSync.mu.Unlock()
+	return c.timeSync.offset
+}
+
+// serverNow returns the current time on the server clock.
+func (c *RestClient) serverNow() time.Time {
+	return time.Now().Add(c.TimeOffset())
+}
+
+// timestamp returns the server time in milliseconds for use as the timestamp
+// parameter of a signed request, refreshing the offset first if it is due.
+func (c *RestClient) timestamp(ctx context.Context) int64 {
+	if c.timeSync.startSync(c.timeSyncInterval) {
+		if err := c.SyncTime(ctx); err != nil {
+			log.Printf("error: failed to sync time with server: %v", err)
+		}
+		c.timeSync.endSync()
+	}
+	return getTimeMillis() + int64(c.TimeOffset()/time.Millisecond)
+}
+
+// startSync returns true if a sync is due every interval and no other sync
+// is in progress. The caller must call endSync when done. A failed sync is
+// not attempted again until the next interval.
+func (s *timeSync) startSync(interval time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if interval <= 0 || s.syncing || time.Since(s.lastSync) < interval {
+		return false
+	}
+	s.syncing = true
+	s.lastSync = time.Now()
+	return true
+}
+
+func (s *timeSync) endSync() {
+	s.mu.Lock()
+	s.syncing = false
+	s.mu.Unlock()
+}
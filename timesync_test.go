@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+// countRequests returns the number of requests the server received for path.
+func countRequests(server *binanceapitest.Server, path string) int {
+	count := 0
+	for _, request := range server.Requests() {
+		if request.Path == path {
+			count++
+		}
+	}
+	return count
+}
+
+func TestTimeSyncBeforeFirstSignedRequest(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+	server.Now = func() time.Time {
+		return time.Now().Add(30 * time.Second)
+	}
+
+	client := server.Client()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetAccount(); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if n := countRequests(server, "/api/v1/time"); n != 1 {
+		t.Errorf("expected 1 time request, got %d", n)
+	}
+	if offset := client.TimeOffset(); offset < 29*time.Second || offset > 31*time.Second {
+		t.Errorf("unexpected offset %v", offset)
+	}
+}
+
+func TestTimeSyncDisabled(t *testing.T) {
+	server := binanceapitest.NewServer()
+	defer server.Close()
+
+	client := server.Client(binanceapi.WithTimeSync(0))
+	if _, err := client.GetAccount(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(server, "/api/v1/time"); n != 0 {
+		t.Errorf("expected no time requests, got %d", n)
+	}
+}
@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2019 Cranky Kernel
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package binanceapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/crankykernel/binanceapi-go"
+	"github.com/crankykernel/binanceapi-go/binanceapitest"
+)
+
+// BenchmarkPostOrder compares placing orders from parallel goroutines over
+// http.DefaultClient, which keeps only 2 idle connections per host, with
+// NewTransport, with and without opening the connections up front.
+func BenchmarkPostOrder(b *testing.B) {
+	benchmarks := []struct {
+		name       string
+		httpClient func() *http.Client
+		warmUp     bool
+	}{
+		{"DefaultClient", func() *http.Client {
+			http.DefaultClient.CloseIdleConnections()
+			return http.DefaultClient
+		}, false},
+		{"NewTransport", func() *http.Client {
+			return &http.Client{Transport: binanceapi.NewTransport()}
+		}, false},
+		{"NewTransportWarmUp", func() *http.Client {
+			return &http.Client{Transport: binanceapi.NewTransport()}
+		}, true},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			server := binanceapitest.NewServer()
+			defer server.Close()
+			server.SetBalance("USDT", 1e15)
+
+			httpClient := bm.httpClient()
+			defer httpClient.CloseIdleConnections()
+			client := server.Client(binanceapi.WithHttpClient(httpClient))
+			if err := client.SyncTime(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+			if bm.warmUp {
+				if err := client.WarmUp(context.Background(), 16); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			order := binanceapi.OrderParameters{
+				Symbol:           "BTCUSDT",
+				Side:             binanceapi.OrderSideBuy,
+				Type:             binanceapi.OrderTypeMarket,
+				Quantity:         0.001,
+				NewOrderRespType: binanceapi.OrderRespTypeAck,
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := client.PostOrder(order); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}